@@ -0,0 +1,207 @@
+package mnee
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact MNEE amount: an integer number of atomic units together
+// with the token's decimals. The zero value is zero atomic units with 0 decimals.
+//
+// Amounts format and marshal to JSON as decimal strings with exactly
+// `decimals` fraction digits (e.g. "12.34000" for 5 decimals), so they
+// round-trip without loss.
+type Amount struct {
+	atomic   uint64
+	decimals uint8
+}
+
+// NewAmount returns an Amount of `atomic` units of a token with `decimals` decimals.
+func NewAmount(atomic uint64, decimals uint8) Amount {
+	return Amount{atomic: atomic, decimals: decimals}
+}
+
+// ParseAmount parses a decimal string such as "12.34" for a token with
+// `decimals` decimals. Signs, exponents and more fraction digits than the
+// token supports are rejected with ErrInvalidAmount.
+func ParseAmount(value string, decimals uint8) (Amount, error) {
+
+	integer, fraction, hasPoint := strings.Cut(value, ".")
+	if (integer == "" && fraction == "") || (hasPoint && fraction == "") || len(fraction) > int(decimals) ||
+		!isDigits(integer) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf("%w: %q with %d decimals", ErrInvalidAmount, value, decimals)
+	}
+
+	var digits string = strings.TrimLeft(integer+fraction+strings.Repeat("0", int(decimals)-len(fraction)), "0")
+	if digits == "" {
+		return Amount{decimals: decimals}, nil
+	}
+
+	atomic, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %q", ErrAmountOverflow, value)
+	}
+
+	return Amount{atomic: atomic, decimals: decimals}, nil
+}
+
+// NewAmount returns an Amount of `atomic` units using the config's decimals.
+func (c *SystemConfig) NewAmount(atomic uint64) Amount {
+	return NewAmount(atomic, c.Decimals)
+}
+
+// ParseAmount parses a decimal string using the config's decimals.
+func (c *SystemConfig) ParseAmount(value string) (Amount, error) {
+	return ParseAmount(value, c.Decimals)
+}
+
+// Amount returns the exact amount of the UTXO.
+func (t MneeTxo) Amount() Amount {
+
+	if t.Data == nil || t.Data.Bsv21 == nil {
+		return Amount{}
+	}
+
+	return NewAmount(t.Data.Bsv21.Amt, t.Data.Bsv21.Decimals)
+}
+
+// Atomic returns the amount in atomic units, as used by TransferMneeDTO.
+func (a Amount) Atomic() uint64 {
+	return a.atomic
+}
+
+// Decimals returns the number of decimals of the token.
+func (a Amount) Decimals() uint8 {
+	return a.decimals
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.atomic == 0
+}
+
+// String formats the amount with exactly Decimals fraction digits, e.g. "12.34000".
+func (a Amount) String() string {
+
+	var digits string = strconv.FormatUint(a.atomic, 10)
+	if a.decimals == 0 {
+		return digits
+	}
+
+	if len(digits) <= int(a.decimals) {
+		digits = strings.Repeat("0", int(a.decimals)-len(digits)+1) + digits
+	}
+
+	return digits[:len(digits)-int(a.decimals)] + "." + digits[len(digits)-int(a.decimals):]
+}
+
+// Add returns a + b. It fails with ErrAmountDecimalsMismatch if the amounts
+// have different decimals and with ErrAmountOverflow if the sum overflows.
+func (a Amount) Add(b Amount) (Amount, error) {
+
+	if a.decimals != b.decimals {
+		return Amount{}, ErrAmountDecimalsMismatch
+	}
+
+	sum, carry := bits.Add64(a.atomic, b.atomic, 0)
+	if carry != 0 {
+		return Amount{}, ErrAmountOverflow
+	}
+
+	return Amount{atomic: sum, decimals: a.decimals}, nil
+}
+
+// Sub returns a - b. It fails with ErrAmountDecimalsMismatch if the amounts
+// have different decimals and with ErrNegativeAmount if b is greater than a.
+func (a Amount) Sub(b Amount) (Amount, error) {
+
+	if a.decimals != b.decimals {
+		return Amount{}, ErrAmountDecimalsMismatch
+	}
+
+	if b.atomic > a.atomic {
+		return Amount{}, ErrNegativeAmount
+	}
+
+	return Amount{atomic: a.atomic - b.atomic, decimals: a.decimals}, nil
+}
+
+// Cmp compares a and b, returning -1, 0 or +1. Amounts with different
+// decimals are compared by value.
+func (a Amount) Cmp(b Amount) int {
+
+	if a.decimals == b.decimals {
+		switch {
+		case a.atomic < b.atomic:
+			return -1
+		case a.atomic > b.atomic:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return a.scaled(b.decimals).Cmp(b.scaled(a.decimals))
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes a decimal string or number. The decimals are taken
+// from the number of fraction digits, which MarshalJSON always writes in full.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+
+	var value string
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		err := json.Unmarshal(data, &value)
+		if err != nil {
+			return err
+		}
+	} else {
+		value = string(data)
+	}
+
+	_, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > 255 {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	parsed, err := ParseAmount(value, uint8(len(fraction)))
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
+
+// scaled returns the atomic units of the amount expressed with
+// max(a.decimals, decimals) decimals, for comparisons across decimals.
+func (a Amount) scaled(decimals uint8) *big.Int {
+
+	var scaled *big.Int = new(big.Int).SetUint64(a.atomic)
+	if decimals > a.decimals {
+		scaled.Mul(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-a.decimals)), nil))
+	}
+
+	return scaled
+}
+
+// isDigits reports whether value only contains ASCII digits.
+func isDigits(value string) bool {
+
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return true
+}
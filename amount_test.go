@@ -0,0 +1,139 @@
+package mnee
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	assertions := assert.New(t)
+
+	t.Log("Test Case 1: Decimal strings are parsed to atomic units...")
+	for value, atomic := range map[string]uint64{
+		"12.34":                 1234000,
+		"0.00001":               1,
+		"7":                     700000,
+		".5":                    50000,
+		"000.10000":             10000,
+		"0":                     0,
+		"184467440737095.51615": math.MaxUint64,
+	} {
+		amount, err := ParseAmount(value, 5)
+		if assertions.NoError(err, value) {
+			assertions.Equal(atomic, amount.Atomic(), value)
+			assertions.Equal(uint8(5), amount.Decimals(), value)
+		}
+	}
+
+	t.Log("Test Case 2: Malformed, over-precise and oversized strings are rejected...")
+	for _, value := range []string{"", ".", "1.", "-1", "+1", "1e5", "1.000001", "1,5", " 1", "0x10"} {
+		_, err := ParseAmount(value, 5)
+		assertions.ErrorIs(err, ErrInvalidAmount, value)
+	}
+	_, err := ParseAmount("184467440737095.51616", 5)
+	assertions.ErrorIs(err, ErrAmountOverflow)
+
+	t.Log("Test Case 3: Amounts format with every decimal...")
+	assertions.Equal("12.34000", NewAmount(1234000, 5).String())
+	assertions.Equal("0.00001", NewAmount(1, 5).String())
+	assertions.Equal("0.00000", NewAmount(0, 5).String())
+	assertions.Equal("42", NewAmount(42, 0).String())
+
+	config := &SystemConfig{Decimals: 5}
+	amount, err := config.ParseAmount("1.5")
+	assertions.NoError(err)
+	assertions.Equal(config.NewAmount(150000), amount)
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	assertions := assert.New(t)
+
+	t.Log("Test Case 1: Add and Sub are exact...")
+	sum, err := NewAmount(10, 5).Add(NewAmount(20, 5))
+	assertions.NoError(err)
+	assertions.Equal(NewAmount(30, 5), sum)
+	difference, err := sum.Sub(NewAmount(30, 5))
+	assertions.NoError(err)
+	assertions.True(difference.IsZero())
+
+	t.Log("Test Case 2: Overflow, negative results and mixed decimals fail...")
+	_, err = NewAmount(math.MaxUint64, 5).Add(NewAmount(1, 5))
+	assertions.ErrorIs(err, ErrAmountOverflow)
+	_, err = NewAmount(1, 5).Sub(NewAmount(2, 5))
+	assertions.ErrorIs(err, ErrNegativeAmount)
+	_, err = NewAmount(1, 5).Add(NewAmount(1, 2))
+	assertions.ErrorIs(err, ErrAmountDecimalsMismatch)
+
+	t.Log("Test Case 3: Cmp compares values across decimals...")
+	assertions.Equal(0, NewAmount(100000, 5).Cmp(NewAmount(100, 2)))
+	assertions.Equal(-1, NewAmount(99999, 5).Cmp(NewAmount(1, 0)))
+	assertions.Equal(1, NewAmount(math.MaxUint64, 0).Cmp(NewAmount(math.MaxUint64, 5)))
+}
+
+func TestAmount_JSON(t *testing.T) {
+	assertions := assert.New(t)
+
+	t.Log("Test Case 1: Amounts round-trip as strings without loss...")
+	original := NewAmount(math.MaxUint64, 5)
+	encoded, err := json.Marshal(original)
+	assertions.NoError(err)
+	assertions.Equal(`"184467440737095.51615"`, string(encoded))
+
+	var decoded Amount
+	assertions.NoError(json.Unmarshal(encoded, &decoded))
+	assertions.Equal(original, decoded)
+
+	t.Log("Test Case 2: Bare JSON numbers are accepted, exponents are not...")
+	assertions.NoError(json.Unmarshal([]byte(`12.50`), &decoded))
+	assertions.Equal(NewAmount(1250, 2), decoded)
+	assertions.ErrorIs(json.Unmarshal([]byte(`1e3`), &decoded), ErrInvalidAmount)
+
+	t.Log("Test Case 3: Balances keep atomic units beyond float64 precision...")
+	var balance BalanceDataDTO
+	assertions.NoError(json.Unmarshal([]byte(`{"amt":9007199254740993,"precised":90071992547.40993,"address":"a"}`), &balance))
+	assertions.Equal(uint64(9007199254740993), balance.Amount(5).Atomic())
+	assertions.Equal("a", *balance.Address)
+}
+
+func TestAmount_TransferAndBalance(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+	})
+
+	t.Log("Test Case 1: Recipients accept exact amounts and the summary returns them...")
+	amount, err := ParseAmount("0.04", 5)
+	assertions.NoError(err)
+	_, summary, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipientAmount(recipient.address, amount).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(4000), summary.TotalTransfer)
+	assertions.Equal("0.00100", summary.FeeAmount().String())
+	assertions.Equal("0.03900", summary.ChangeAmount().String())
+	assertions.Equal("0.08000", summary.TotalInputAmount().String())
+
+	t.Log("Test Case 2: Amounts with other decimals are rejected...")
+	_, _, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipientAmount(recipient.address, NewAmount(4, 2)).
+		Build(context.Background())
+	assertions.ErrorIs(err, ErrAmountDecimalsMismatch)
+
+	t.Log("Test Case 3: Balances convert to exact amounts...")
+	balances, err := fixture.mnee.GetBalances(context.Background(), []string{sender.address})
+	if assertions.NoError(err) && assertions.Len(balances, 1) {
+		assertions.Equal("0.08000", balances[0].Amount(fixture.config.Decimals).String())
+	}
+}
@@ -0,0 +1,122 @@
+package mnee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxErrorBodySize caps how much of an error response body is kept in an APIError.
+const maxErrorBodySize int64 = 64 << 10
+
+// recordNotFoundMessage is the message the cosigner uses for unknown records.
+const recordNotFoundMessage string = "record not found"
+
+// APIError is returned by every API method when the MNEE API responds with a
+// non-200 status. Use errors.As to inspect it, or errors.Is with ErrForbidden,
+// ErrNotFound, ErrRateLimited and ErrServerUnavailable to classify it.
+type APIError struct {
+	// StatusCode is the HTTP status returned by the API.
+	StatusCode int
+	// Endpoint is the API path that was called (e.g. "/v1/utxos"), without query parameters.
+	Endpoint string
+	// RequestID is the value of the X-Request-Id response header, if any.
+	RequestID string
+	// Body is the raw response body (truncated to 64KiB).
+	Body []byte
+	// Message is the "message" field of the JSON error body, if present.
+	Message string
+	// RetryAfter is the delay requested by the Retry-After response header, if any.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+
+	var message string = e.Message
+	if message == "" && e.StatusCode == http.StatusForbidden {
+		message = ErrForbidden.Error()
+	}
+
+	if message == "" {
+		return fmt.Sprintf("status received from mnee-cosigner %s -> %d", e.Endpoint, e.StatusCode)
+	}
+
+	return fmt.Sprintf("status received from mnee-cosigner %s -> %d: %s", e.Endpoint, e.StatusCode, message)
+}
+
+// Is reports whether the error matches one of the status sentinels.
+func (e *APIError) Is(target error) bool {
+
+	switch target {
+
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Message == recordNotFoundMessage
+
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+
+	case ErrServerUnavailable:
+		return e.StatusCode == http.StatusBadGateway ||
+			e.StatusCode == http.StatusServiceUnavailable ||
+			e.StatusCode == http.StatusGatewayTimeout
+	}
+
+	return false
+}
+
+// newAPIError consumes the body of a non-200 response and converts it into an *APIError.
+func newAPIError(response *http.Response, endpoint string) error {
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	if err != nil {
+		return err
+	}
+
+	var apiError APIError = APIError{
+		StatusCode: response.StatusCode,
+		Endpoint:   endpoint,
+		RequestID:  response.Header.Get("X-Request-Id"),
+		Body:       body,
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+	}
+
+	var errorResponse map[string]any
+	if json.Unmarshal(body, &errorResponse) == nil {
+		if errorMessage, ok := errorResponse["message"].(string); ok {
+			apiError.Message = errorMessage
+		}
+	}
+
+	return &apiError
+}
+
+// isRecordNotFound reports whether the error is the cosigner's "record not found" response.
+func isRecordNotFound(err error) bool {
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	return apiError.Message == recordNotFoundMessage
+}
+
+// isRejected reports whether the cosigner definitively refused a request with
+// a 4xx other than 429, so a submitted transfer is known not to have landed.
+func isRejected(err error) bool {
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	return apiError.StatusCode >= http.StatusBadRequest && apiError.StatusCode < http.StatusInternalServerError &&
+		apiError.StatusCode != http.StatusTooManyRequests
+}
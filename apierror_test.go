@@ -0,0 +1,72 @@
+package mnee
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_Classification(t *testing.T) {
+	assertions := assert.New(t)
+
+	var status int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if !assertions.NoError(err) {
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+		message  string
+	}{
+		{"forbidden", http.StatusForbidden, `{"message":"invalid key"}`, ErrForbidden, "invalid key"},
+		{"not found", http.StatusNotFound, `not json`, ErrNotFound, ""},
+		{"record not found", http.StatusBadRequest, `{"message":"record not found"}`, ErrNotFound, "record not found"},
+		{"rate limited", http.StatusTooManyRequests, `{"message":"slow down"}`, ErrRateLimited, "slow down"},
+		{"unavailable", http.StatusServiceUnavailable, ``, ErrServerUnavailable, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Logf("Test Case: %s...", testCase.name)
+		status = testCase.status
+		body = testCase.body
+
+		_, err = m.GetUnspentTxos(context.Background(), []string{"1BoatSLRHtKNngkdXEeobR76b53LETtpyT"})
+
+		var apiError *APIError
+		if !assertions.True(errors.As(err, &apiError), "Error should be an *APIError") {
+			continue
+		}
+		assertions.ErrorIs(err, testCase.sentinel)
+		assertions.Equal(testCase.status, apiError.StatusCode)
+		assertions.Equal("/v1/utxos", apiError.Endpoint)
+		assertions.Equal("req-123", apiError.RequestID)
+		assertions.Equal(testCase.body, string(apiError.Body))
+		assertions.Equal(testCase.message, apiError.Message)
+	}
+
+	t.Log("Test Case: a validation error matches no status sentinel...")
+	status = http.StatusBadRequest
+	body = `{"message":"invalid address"}`
+	_, err = m.GetBalances(context.Background(), []string{"bad"})
+	assertions.NotErrorIs(err, ErrForbidden)
+	assertions.NotErrorIs(err, ErrNotFound)
+	assertions.NotErrorIs(err, ErrRateLimited)
+	assertions.NotErrorIs(err, ErrServerUnavailable)
+	assertions.Contains(err.Error(), "invalid address")
+}
@@ -0,0 +1,90 @@
+package mnee
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Authenticator attaches the API token to every request sent by the client.
+// Implementations must be safe for concurrent use.
+type Authenticator interface {
+	// Authenticate adds the current token to the request.
+	Authenticate(request *http.Request, token string) error
+}
+
+// HeaderAuthenticator sends the token in a header, keeping it out of URLs and
+// so out of proxy logs and net/http errors. It is the default.
+type HeaderAuthenticator struct {
+	// Header carries the raw token. When empty the token is sent as
+	// "Authorization: Bearer <token>".
+	Header string
+}
+
+// Authenticate implements Authenticator.
+func (a HeaderAuthenticator) Authenticate(request *http.Request, token string) error {
+
+	if a.Header == "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+
+	request.Header.Set(a.Header, token)
+	return nil
+}
+
+// QueryAuthenticator sends the token as the auth_token query parameter, as
+// earlier versions of the SDK did, for deployments that do not accept the header.
+type QueryAuthenticator struct{}
+
+// Authenticate implements Authenticator.
+func (QueryAuthenticator) Authenticate(request *http.Request, token string) error {
+
+	var query url.Values = request.URL.Query()
+	query.Set("auth_token", token)
+	request.URL.RawQuery = query.Encode()
+	return nil
+}
+
+// WithAuthenticator replaces the default HeaderAuthenticator, e.g. with
+// QueryAuthenticator for compatibility.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(m *MNEE) error {
+		if authenticator == nil {
+			return ErrNilAuthenticator
+		}
+
+		m.authenticator = authenticator
+
+		return nil
+	}
+}
+
+// SetAuthToken replaces the API token used by every subsequent request, so a
+// long-lived client can pick up a rotated key. It is safe for concurrent use.
+func (m *MNEE) SetAuthToken(token string) {
+	m.mneeToken.Store(&token)
+}
+
+// redactedURLError masks the auth_token query parameter in the URL of a
+// net/http error, which quotes the full request URL.
+func redactedURLError(err error) error {
+
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	parsedURL, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+
+	var query url.Values = parsedURL.Query()
+	if !query.Has("auth_token") {
+		return err
+	}
+
+	query.Set("auth_token", "REDACTED")
+	parsedURL.RawQuery = query.Encode()
+	return &url.Error{Op: urlErr.Op, URL: parsedURL.String(), Err: urlErr.Err}
+}
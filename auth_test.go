@@ -0,0 +1,101 @@
+package mnee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	var mutex sync.Mutex
+	var lastRequest *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		lastRequest = r
+		mutex.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer current" && r.Header.Get("X-Api-Key") != "current" &&
+			r.URL.Query().Get("auth_token") != "current" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"forbidden"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	last := func() *http.Request {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return lastRequest
+	}
+
+	t.Log("Test Case 1: The token is sent as a bearer header by default...")
+	m, err := NewMneeInstance(EnvCustom, "current", WithBaseURL(server.URL))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.GetPaginatedUnspentTxos(ctx, []string{"address"}, 2, 10)
+	assertions.NoError(err)
+	assertions.Equal("Bearer current", last().Header.Get("Authorization"))
+	assertions.False(last().URL.Query().Has("auth_token"))
+	assertions.Equal("2", last().URL.Query().Get("page"))
+
+	t.Log("Test Case 2: Rotated tokens are used by subsequent requests...")
+	m, err = NewMneeInstance(EnvCustom, "stale", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.GetUnspentTxos(ctx, []string{"address"})
+	assertions.True(isRejected(err))
+	m.SetAuthToken("current")
+	_, err = m.GetUnspentTxos(ctx, []string{"address"})
+	assertions.NoError(err)
+
+	t.Log("Test Case 3: The query parameter and custom headers remain available...")
+	m, err = NewMneeInstance(EnvCustom, "current", WithBaseURL(server.URL), WithAuthenticator(QueryAuthenticator{}))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.GetPaginatedUnspentTxos(ctx, []string{"address"}, 3, 10)
+	assertions.NoError(err)
+	assertions.Equal("current", last().URL.Query().Get("auth_token"))
+	assertions.Equal("3", last().URL.Query().Get("page"))
+	assertions.Empty(last().Header.Get("Authorization"))
+
+	m, err = NewMneeInstance(EnvCustom, "current", WithBaseURL(server.URL), WithAuthenticator(HeaderAuthenticator{Header: "X-Api-Key"}))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.GetUnspentTxos(ctx, []string{"address"})
+	assertions.NoError(err)
+	assertions.Equal("current", last().Header.Get("X-Api-Key"))
+
+	_, err = NewMneeInstance(EnvCustom, "current", WithBaseURL(server.URL), WithAuthenticator(nil))
+	assertions.ErrorIs(err, ErrNilAuthenticator)
+
+	t.Log("Test Case 4: Transport errors never quote the token...")
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	m, err = NewMneeInstance(EnvCustom, "secret-token", WithBaseURL(closed.URL),
+		WithAuthenticator(QueryAuthenticator{}), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.GetUnspentTxos(ctx, []string{"address"})
+	var urlErr *url.Error
+	if assertions.ErrorAs(err, &urlErr) {
+		assertions.NotContains(err.Error(), "secret-token")
+		assertions.Contains(urlErr.URL, "auth_token=REDACTED")
+	}
+}
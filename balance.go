@@ -1,66 +1,86 @@
 package mnee
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 // GetBalances fetches the MNEE balance for a list of addresses.
 // It returns a slice of BalanceDataDTO, one for each address.
-func (m *MNEE) GetBalances(ctx context.Context, addresses []string) ([]BalanceDataDTO, error) {
+func (m *MNEE) GetBalances(ctx context.Context, addresses []string) (_ []BalanceDataDTO, err error) {
+
+	ctx, span := m.startSpan(ctx, "GetBalances", Attribute{Key: "mnee.address_count", Value: len(addresses)})
+	defer func() { span.End(err) }()
 
 	addressesBuffer, err := json.Marshal(&addresses)
 	if err != nil {
 		return nil, err
 	}
 
-	balancesRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		(m.mneeURL + "/v2/balance?auth_token=" + m.mneeToken),
-		bytes.NewBuffer(addressesBuffer),
-	)
+	balancesResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodPost,
+		endpoint:   "/v2/balance",
+		body:       addressesBuffer,
+		headers:    map[string]string{"Content-Type": "application/json"},
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	balancesRequest.Header.Set("Content-Type", "application/json")
+	defer balancesResponse.Body.Close()
 
 	var balances []BalanceDataDTO = make([]BalanceDataDTO, 0)
-	balancesResponse, err := m.httpClient.Do(balancesRequest)
+	err = json.NewDecoder(balancesResponse.Body).Decode(&balances)
 	if err != nil {
 		return nil, err
 	}
 
-	defer balancesResponse.Body.Close()
+	return balances, nil
+}
+
+// Amount returns the balance as an exact Amount of a token with `decimals`
+// decimals (SystemConfig.Decimals). Balances decoded from the API keep every
+// atomic unit; hand-built ones fall back to rounding Amt.
+func (b BalanceDataDTO) Amount(decimals uint8) Amount {
 
-	if balancesResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
+	if b.atomic != nil {
+		return NewAmount(*b.atomic, decimals)
 	}
 
-	if balancesResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(balancesResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
+	return NewAmount(uint64(math.Round(max(b.Amt, 0))), decimals)
+}
 
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", balancesResponse.StatusCode)
-		}
+// UnmarshalJSON decodes a balance, keeping the exact atomic amount alongside Amt.
+func (b *BalanceDataDTO) UnmarshalJSON(data []byte) error {
 
-		return nil, errors.New(errorMessage)
+	type balanceData BalanceDataDTO
+	var decoded struct {
+		balanceData
+		Amt json.Number `json:"amt"`
 	}
 
-	err = json.NewDecoder(balancesResponse.Body).Decode(&balances)
+	err := json.Unmarshal(data, &decoded)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return balances, nil
+	*b = BalanceDataDTO(decoded.balanceData)
+	if decoded.Amt == "" {
+		return nil
+	}
+
+	b.Amt, err = decoded.Amt.Float64()
+	if err != nil {
+		return err
+	}
+
+	if atomic, err := strconv.ParseUint(decoded.Amt.String(), 10, 64); err == nil {
+		b.atomic = &atomic
+	}
+
+	return nil
 }
@@ -0,0 +1,804 @@
+package mnee
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// TransferOutputKind identifies the role of an output in a MNEE transfer.
+type TransferOutputKind string
+
+const (
+	// OutputRecipient is an output paying one of the requested recipients.
+	OutputRecipient TransferOutputKind = "recipient"
+	// OutputFee is the output paying the MNEE fee to the configured fee address.
+	OutputFee TransferOutputKind = "fee"
+	// OutputChange is an output returning the remaining input amount to the sender.
+	OutputChange TransferOutputKind = "change"
+)
+
+// TransferOutput describes a single MNEE output created by a TransferBuilder.
+type TransferOutput struct {
+	Kind    TransferOutputKind `json:"kind"`
+	Address string             `json:"address"`
+	Amount  uint64             `json:"amount"`
+}
+
+// TransferSummary describes the transaction produced by a TransferBuilder.
+// All amounts are in atomic units.
+type TransferSummary struct {
+	Inputs        []MneeTxo        `json:"inputs"`
+	Outputs       []TransferOutput `json:"outputs"`
+	TotalInput    uint64           `json:"totalInput"`
+	TotalTransfer uint64           `json:"totalTransfer"`
+	Fee           uint64           `json:"fee"`
+	FeeTier       *Fee             `json:"feeTier,omitempty"`
+	Change        uint64           `json:"change"`
+	ChangeAddress string           `json:"changeAddress,omitempty"`
+	CoinSelector  string           `json:"coinSelector"`
+	// Decimals is the token's decimals, used by the *Amount accessors.
+	Decimals uint8 `json:"decimals"`
+}
+
+// TotalInputAmount returns TotalInput as an exact Amount.
+func (s *TransferSummary) TotalInputAmount() Amount {
+	return NewAmount(s.TotalInput, s.Decimals)
+}
+
+// TotalTransferAmount returns TotalTransfer as an exact Amount.
+func (s *TransferSummary) TotalTransferAmount() Amount {
+	return NewAmount(s.TotalTransfer, s.Decimals)
+}
+
+// FeeAmount returns Fee as an exact Amount.
+func (s *TransferSummary) FeeAmount() Amount {
+	return NewAmount(s.Fee, s.Decimals)
+}
+
+// ChangeAmount returns Change as an exact Amount.
+func (s *TransferSummary) ChangeAmount() Amount {
+	return NewAmount(s.Change, s.Decimals)
+}
+
+// ChangeAddressFunc chooses the change address once the inputs of a transfer
+// are known. It receives the context of the Build, Sign or Submit call.
+type ChangeAddressFunc func(ctx context.Context, inputs []MneeTxo) (string, error)
+
+// FeePolicy selects the MNEE fee tier for the amount sent to addresses
+// that are not among the transfer's inputs. It reports false when no tier applies.
+type FeePolicy func(fees []Fee, amount uint64) (Fee, bool)
+
+// TieredFeePolicy is the default FeePolicy. It returns the first tier of the
+// MNEE system config whose [MinAmt, MaxAmt] range contains the amount.
+func TieredFeePolicy(fees []Fee, amount uint64) (Fee, bool) {
+
+	for _, fee := range fees {
+		if amount >= fee.MinAmt && amount <= fee.MaxAmt {
+			return fee, true
+		}
+	}
+
+	return Fee{}, false
+}
+
+// feeBase returns the amount a transfer's fee tier is chosen on: every output
+// except the fee and the outputs returning to one of the input addresses, so
+// change sent to another address counts like a recipient.
+func feeBase(outputs []TransferOutput, inputAddresses []string, feeAddress string) uint64 {
+
+	var base uint64
+	for _, output := range outputs {
+		if output.Kind == OutputFee || output.Address == feeAddress || slices.Contains(inputAddresses, output.Address) {
+			continue
+		}
+
+		base += output.Amount
+	}
+
+	return base
+}
+
+// TransferBuilder assembles MNEE transfer transactions. It parses the signing keys,
+// creates the recipient inscriptions, selects UTXOs, applies the fee tier and
+// creates the change output. SynchronousTransfer, AsynchronousTransfer and
+// PartialSign are thin wrappers around it.
+//
+// A TransferBuilder is not safe for concurrent use.
+type TransferBuilder struct {
+	mnee          *MNEE
+	recipients    []TransferMneeDTO
+	amounts       []Amount
+	wifs          []string
+	signers       []Signer
+	addresses     []string
+	txos          []MneeTxo
+	withTxos      bool
+	changeAddress string
+	changeFunc    ChangeAddressFunc
+	// changeResolver picks a change address that does not depend on the inputs,
+	// resolved at most once per build. It is set by HDWallet.NewTransferBuilder.
+	changeResolver func(ctx context.Context) (string, error)
+	changeOutputs  int
+	feePolicy      FeePolicy
+	coinSelector   CoinSelector
+	sendAll        string
+}
+
+// NewTransferBuilder returns an empty TransferBuilder bound to the client.
+func (m *MNEE) NewTransferBuilder() *TransferBuilder {
+	return &TransferBuilder{
+		mnee:          m,
+		feePolicy:     TieredFeePolicy,
+		coinSelector:  APIOrderSelector{},
+		changeOutputs: 1,
+	}
+}
+
+// AddRecipient adds a recipient receiving `amount` atomic units.
+func (b *TransferBuilder) AddRecipient(address string, amount uint64) *TransferBuilder {
+	b.recipients = append(b.recipients, TransferMneeDTO{Address: address, Amount: amount})
+	return b
+}
+
+// AddRecipientAmount adds a recipient receiving an exact Amount. Build fails
+// with ErrAmountDecimalsMismatch if its decimals differ from the token's.
+func (b *TransferBuilder) AddRecipientAmount(address string, amount Amount) *TransferBuilder {
+	b.amounts = append(b.amounts, amount)
+	return b.AddRecipient(address, amount.Atomic())
+}
+
+// AddRecipients adds several recipients at once.
+func (b *TransferBuilder) AddRecipients(recipients ...TransferMneeDTO) *TransferBuilder {
+	b.recipients = append(b.recipients, recipients...)
+	return b
+}
+
+// SendAll sends whatever the spendable UTXOs hold after the other recipients
+// and the fee to `address`. The amount is solved against the fee tiers, so it
+// plus its fee uses up the balance; at a tier boundary the remainder returns as
+// change. Every candidate UTXO is spent, bypassing the coin selector, and the
+// build fails with ErrInsufficientMneeBalance if nothing is left to send.
+func (b *TransferBuilder) SendAll(address string) *TransferBuilder {
+	b.sendAll = address
+	return b
+}
+
+// WithWIFs adds the private keys (in WIF format) whose UTXOs may be spent and
+// which are used to sign the inputs they own.
+func (b *TransferBuilder) WithWIFs(wifs ...string) *TransferBuilder {
+	b.wifs = append(b.wifs, wifs...)
+	return b
+}
+
+// WithSigner adds a Signer whose addresses' UTXOs may be spent and which signs
+// the inputs they own. Use it to keep keys in memory-safe or remote signing services.
+func (b *TransferBuilder) WithSigner(signer Signer) *TransferBuilder {
+	if signer != nil {
+		b.signers = append(b.signers, signer)
+	}
+	return b
+}
+
+// WithAddresses adds addresses whose UTXOs may be spent without providing their keys.
+// Inputs owned by these addresses are left unsigned by Sign.
+func (b *TransferBuilder) WithAddresses(addresses ...string) *TransferBuilder {
+	b.addresses = append(b.addresses, addresses...)
+	return b
+}
+
+// WithInputs restricts UTXO selection to the provided, pre-fetched UTXOs instead
+// of fetching them with GetUnspentTxos.
+func (b *TransferBuilder) WithInputs(txos []MneeTxo) *TransferBuilder {
+	b.txos = txos
+	b.withTxos = true
+	return b
+}
+
+// WithChangeAddress sends the change to the given address. By default the
+// change goes to the owner of the last selected UTXO. Change sent to an address
+// that is not among the inputs counts toward the fee tier, like a recipient.
+func (b *TransferBuilder) WithChangeAddress(address string) *TransferBuilder {
+	b.changeAddress = address
+	b.changeResolver = nil
+	return b
+}
+
+// WithChangeAddressFunc lets a callback pick the change address from the selected
+// inputs. It takes precedence over WithChangeAddress, and its address is priced
+// the same way.
+func (b *TransferBuilder) WithChangeAddressFunc(changeFunc ChangeAddressFunc) *TransferBuilder {
+	b.changeFunc = changeFunc
+	b.changeResolver = nil
+	return b
+}
+
+// WithChangeOutputs splits the change into `count` outputs of (almost) equal
+// amounts, any remainder going to the first one. The default is a single output.
+func (b *TransferBuilder) WithChangeOutputs(count int) *TransferBuilder {
+	if count > 0 {
+		b.changeOutputs = count
+	}
+	return b
+}
+
+// WithFeePolicy overrides how the fee tier is chosen. The default is TieredFeePolicy.
+func (b *TransferBuilder) WithFeePolicy(policy FeePolicy) *TransferBuilder {
+	if policy != nil {
+		b.feePolicy = policy
+	}
+	return b
+}
+
+// WithCoinSelector chooses the UTXO selection strategy. The default is APIOrderSelector.
+func (b *TransferBuilder) WithCoinSelector(selector CoinSelector) *TransferBuilder {
+	if selector != nil {
+		b.coinSelector = selector
+	}
+	return b
+}
+
+// Build creates the unsigned transfer transaction and its summary.
+func (b *TransferBuilder) Build(ctx context.Context) (_ *transaction.Transaction, _ *TransferSummary, err error) {
+
+	ctx, span := b.mnee.startSpan(ctx, "TransferBuilder.Build", Attribute{Key: "mnee.recipient_count", Value: len(b.recipients)})
+	defer func() { span.End(err) }()
+
+	mneeTransaction, summary, _, err := b.build(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mneeTransaction, summary, nil
+}
+
+// Sign creates the transfer transaction and signs every input owned by the
+// provided WIFs or signers with ForkID|All|AnyOneCanPay, leaving room for the cosigner.
+func (b *TransferBuilder) Sign(ctx context.Context) (_ *transaction.Transaction, _ *TransferSummary, err error) {
+
+	ctx, span := b.mnee.startSpan(ctx, "TransferBuilder.Sign", Attribute{Key: "mnee.recipient_count", Value: len(b.recipients)})
+	defer func() { span.End(err) }()
+
+	return b.sign(ctx)
+}
+
+// sign is Sign without a span of its own, for the methods that sign as part of
+// a larger operation and report the transfer's attributes on their own span.
+func (b *TransferBuilder) sign(ctx context.Context) (*transaction.Transaction, *TransferSummary, error) {
+
+	mneeTransaction, summary, addressToSigner, err := b.build(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = b.signInputs(ctx, mneeTransaction, summary, addressToSigner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mneeTransaction, summary, nil
+}
+
+// SubmitSync signs the transfer and submits it to the synchronous transfer
+// endpoint, waiting for the final cosigned transaction.
+func (b *TransferBuilder) SubmitSync(ctx context.Context) (_ *TransferResponseDTO, err error) {
+
+	ctx, span := b.mnee.startSpan(ctx, "TransferBuilder.SubmitSync", Attribute{Key: "mnee.recipient_count", Value: len(b.recipients)})
+	defer func() { span.End(err) }()
+
+	response, _, err := b.submitSync(ctx)
+	return response, err
+}
+
+// submitSync is SubmitSync, also returning the transfer's summary.
+func (b *TransferBuilder) submitSync(ctx context.Context) (*TransferResponseDTO, *TransferSummary, error) {
+
+	mneeTransaction, summary, err := b.signReserved(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := b.mnee.submitSync(ctx, mneeTransaction.Bytes())
+	b.mnee.instrumentation.RecordTransfer(ctx, newTransferMetric("sync", summary, err))
+	if b.mnee.reservations != nil {
+		var cosignedHex *string
+		if response != nil {
+			cosignedHex = response.Txhex
+		}
+
+		b.mnee.reservations.settle(summary, mneeTransaction, cosignedHex, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, summary, nil
+}
+
+// SubmitAsync signs the transfer and submits it to the asynchronous transfer
+// endpoint, returning the ticket ID.
+func (b *TransferBuilder) SubmitAsync(ctx context.Context, callbackURL *string, callbackSecret *string) (_ *string, err error) {
+
+	ctx, span := b.mnee.startSpan(ctx, "TransferBuilder.SubmitAsync", Attribute{Key: "mnee.recipient_count", Value: len(b.recipients)})
+	defer func() { span.End(err) }()
+
+	mneeTransaction, summary, err := b.signReserved(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketID, err := b.mnee.submitAsync(ctx, mneeTransaction.Bytes(), callbackURL, callbackSecret)
+	b.mnee.instrumentation.RecordTransfer(ctx, newTransferMetric("async", summary, err))
+	if b.mnee.reservations != nil {
+		// The final txid is only known once the ticket succeeds, so async change is never chained.
+		b.mnee.reservations.settle(summary, mneeTransaction, nil, err)
+	}
+
+	return ticketID, err
+}
+
+// signReserved is Sign for submissions. With WithUTXOReservation the selected
+// inputs are reserved before signing, re-selecting them if a concurrent transfer took them first.
+func (b *TransferBuilder) signReserved(ctx context.Context) (*transaction.Transaction, *TransferSummary, error) {
+
+	if b.mnee.reservations == nil {
+		return b.sign(ctx)
+	}
+
+	for range maxReservationAttempts {
+		mneeTransaction, summary, addressToSigner, err := b.build(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !b.mnee.reservations.reserve(summary.Inputs) {
+			continue
+		}
+
+		err = b.signInputs(ctx, mneeTransaction, summary, addressToSigner)
+		if err != nil {
+			b.mnee.reservations.release(summary.Inputs)
+			return nil, nil, err
+		}
+
+		return mneeTransaction, summary, nil
+	}
+
+	return nil, nil, ErrUTXOsReserved
+}
+
+// signInputs signs every input whose owner has a signer with ForkID|All|AnyOneCanPay.
+func (b *TransferBuilder) signInputs(ctx context.Context, mneeTransaction *transaction.Transaction, summary *TransferSummary,
+	addressToSigner map[string]Signer) error {
+
+	for i, txo := range summary.Inputs {
+		signer, ok := addressToSigner[txo.Owners[0]]
+		if !ok {
+			continue
+		}
+
+		mneeTransaction.Inputs[i].UnlockingScriptTemplate = &signerUnlocker{
+			ctx:          ctx,
+			signer:       signer,
+			address:      txo.Owners[0],
+			sighashFlags: sighash.ForkID | sighash.All | sighash.AnyOneCanPay,
+		}
+	}
+
+	b.mnee.logger.LogAttrs(ctx, slog.LevelDebug, "mnee transfer signing", slog.Int("inputs", len(summary.Inputs)))
+
+	return mneeTransaction.Sign()
+}
+
+// build runs the transfer pipeline shared by every entry point. It returns the
+// unsigned transaction, its summary and the signer controlling each address.
+func (b *TransferBuilder) build(ctx context.Context) (*transaction.Transaction, *TransferSummary, map[string]Signer, error) {
+
+	var signers []Signer = b.signers
+	if len(b.wifs) > 0 {
+		wifSigner, err := NewWIFSigner(b.wifs...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		signers = append([]Signer{wifSigner}, b.signers...)
+	}
+
+	addressToSigner, addresses, err := signerAddresses(ctx, signers)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, address := range b.addresses {
+		if !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	config, err := b.mnee.GetConfig(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if config.Approver == nil || config.FeeAddress == nil || config.Fees == nil || config.TokenId == nil {
+		return nil, nil, nil, ErrInvalidConfig
+	}
+
+	approverPubKey, err := primitives.PublicKeyFromString(*config.Approver)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, amount := range b.amounts {
+		if amount.Decimals() != config.Decimals {
+			return nil, nil, nil, fmt.Errorf("%w: amount %s has %d decimals, the token has %d",
+				ErrAmountDecimalsMismatch, amount, amount.Decimals(), config.Decimals)
+		}
+	}
+
+	var mneeTransaction *transaction.Transaction = transaction.NewTransaction()
+	var summary TransferSummary = TransferSummary{
+		Inputs:   make([]MneeTxo, 0),
+		Outputs:  make([]TransferOutput, 0, len(b.recipients)+2),
+		Decimals: config.Decimals,
+	}
+
+	for _, dto := range b.recipients {
+		if dto.Amount == 0 {
+			return nil, nil, nil, ErrTransferAmountGreaterThan0
+		}
+
+		err = addTransferOutput(mneeTransaction, dto.Address, dto.Amount, approverPubKey, *config.TokenId)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		summary.Outputs = append(summary.Outputs, TransferOutput{Kind: OutputRecipient, Address: dto.Address, Amount: dto.Amount})
+		summary.TotalTransfer += dto.Amount
+	}
+
+	var txos []MneeTxo = make([]MneeTxo, 0)
+	if b.withTxos {
+		txos = b.txos
+	} else {
+		txos, err = b.mnee.GetUnspentTxos(ctx, addresses)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var candidates []MneeTxo = make([]MneeTxo, 0, len(txos))
+	for i := range txos {
+		if txos[i].Data == nil || txos[i].Data.Bsv21 == nil || txos[i].Txid == nil ||
+			txos[i].Script == nil || txos[i].Data.Bsv21.Amt == 0 ||
+			len(txos[i].Owners) == 0 {
+			continue
+		}
+
+		if !slices.Contains(addresses, txos[i].Owners[0]) {
+			continue
+		}
+
+		candidates = append(candidates, txos[i])
+	}
+
+	if b.mnee.reservations != nil {
+		candidates = b.mnee.reservations.available(candidates, addresses, !b.withTxos)
+	}
+
+	var recipients []TransferMneeDTO = b.recipients
+	var coinSelector CoinSelector = b.coinSelector
+	if b.sendAll != "" {
+		var available uint64
+		var candidateAddresses []string = make([]string, 0)
+		for _, txo := range candidates {
+			available += txo.Data.Bsv21.Amt
+			if !slices.Contains(candidateAddresses, txo.Owners[0]) {
+				candidateAddresses = append(candidateAddresses, txo.Owners[0])
+			}
+		}
+
+		// Recipients outside the input addresses pay the fee, as in the fee loop below.
+		var feeBase uint64
+		for _, dto := range recipients {
+			if !slices.Contains(candidateAddresses, dto.Address) {
+				feeBase += dto.Amount
+			}
+		}
+
+		if available <= summary.TotalTransfer {
+			return nil, nil, nil, ErrInsufficientMneeBalance
+		}
+
+		amount, ok := solveMaxSendable(available-summary.TotalTransfer, feeBase,
+			!slices.Contains(candidateAddresses, b.sendAll), config.Fees, b.feePolicy)
+		if !ok {
+			return nil, nil, nil, ErrInsufficientMneeBalance
+		}
+
+		err = addTransferOutput(mneeTransaction, b.sendAll, amount, approverPubKey, *config.TokenId)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		summary.Outputs = append(summary.Outputs, TransferOutput{Kind: OutputRecipient, Address: b.sendAll, Amount: amount})
+		summary.TotalTransfer += amount
+		recipients = append(slices.Clone(recipients), TransferMneeDTO{Address: b.sendAll, Amount: amount})
+		coinSelector = sweepSelector{}
+	}
+
+	// The first target assumes that recipients outside the spendable addresses pay the fee.
+	var target uint64 = summary.TotalTransfer
+	if estimatedFee, ok := b.feePolicy(config.Fees, feeBase(summary.Outputs, addresses, *config.FeeAddress)); ok {
+		target += estimatedFee.Fee
+	}
+
+	var changeFunc ChangeAddressFunc = b.changeFunc
+	if changeFunc == nil && b.changeResolver != nil {
+		var resolved *string
+		changeFunc = func(ctx context.Context, inputs []MneeTxo) (string, error) {
+			if resolved == nil {
+				address, err := b.changeResolver(ctx)
+				if err != nil {
+					return "", err
+				}
+
+				resolved = &address
+			}
+
+			return *resolved, nil
+		}
+	}
+
+	var settlement transferSettlement
+	var settled bool
+	summary.CoinSelector = coinSelector.Name()
+	for !settled {
+		txos = coinSelector.Select(candidates, target)
+		b.mnee.logger.LogAttrs(ctx, slog.LevelDebug, "mnee transfer inputs selected",
+			slog.String("selector", summary.CoinSelector), slog.Int("candidates", len(candidates)),
+			slog.Int("selected", len(txos)), slog.Uint64("target", target))
+
+		for i := range txos {
+			settlement, settled, err = b.settle(ctx, txos[:i+1], summary.Outputs, summary.TotalTransfer, config, changeFunc)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			if settled {
+				break
+			}
+		}
+
+		// Only the selected inputs' addresses are exempt from the fee, so the
+		// selection may not cover the fee it actually pays: select again for it.
+		var next uint64 = b.selectionTarget(txos, summary.Outputs, summary.TotalTransfer, target, config)
+		if settled || next <= target {
+			break
+		}
+
+		target = next
+	}
+
+	if !settled {
+		b.mnee.logger.LogAttrs(ctx, slog.LevelDebug, "mnee transfer insufficient balance",
+			slog.Uint64("input", settlement.totalInput), slog.Uint64("transfer", summary.TotalTransfer))
+		return nil, nil, nil, ErrInsufficientMneeBalance
+	}
+
+	for _, txo := range settlement.inputs {
+		scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		err = mneeTransaction.AddInputFrom(*txo.Txid, uint32(txo.Vout), hex.EncodeToString(scriptBytes), uint64(txo.Satoshis), nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	summary.Inputs = settlement.inputs
+	summary.TotalInput = settlement.totalInput
+	summary.Fee = settlement.fee.Fee
+	summary.FeeTier = &settlement.fee
+	summary.Change = settlement.change
+
+	if summary.Fee > 0 {
+		err = addTransferOutput(mneeTransaction, *config.FeeAddress, summary.Fee, approverPubKey, *config.TokenId)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		summary.Outputs = append(summary.Outputs, TransferOutput{Kind: OutputFee, Address: *config.FeeAddress, Amount: summary.Fee})
+	}
+
+	if summary.Change > 0 {
+		summary.ChangeAddress = settlement.changeAddress
+		for _, changeAmount := range splitChange(summary.Change, b.changeOutputs) {
+			err = addTransferOutput(mneeTransaction, summary.ChangeAddress, changeAmount, approverPubKey, *config.TokenId)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			summary.Outputs = append(summary.Outputs, TransferOutput{Kind: OutputChange, Address: summary.ChangeAddress, Amount: changeAmount})
+		}
+	}
+
+	spanFromContext(ctx).SetAttributes(
+		Attribute{Key: "mnee.fee", Value: summary.Fee},
+		Attribute{Key: "mnee.fee_tier_min", Value: summary.FeeTier.MinAmt},
+		Attribute{Key: "mnee.fee_tier_max", Value: summary.FeeTier.MaxAmt},
+		Attribute{Key: "mnee.input_count", Value: len(summary.Inputs)},
+	)
+	b.mnee.logger.LogAttrs(ctx, slog.LevelDebug, "mnee transfer fee tier",
+		slog.Uint64("fee", summary.Fee), slog.Uint64("tier_min", summary.FeeTier.MinAmt),
+		slog.Uint64("tier_max", summary.FeeTier.MaxAmt), slog.Int("inputs", len(summary.Inputs)),
+		slog.Uint64("transfer", summary.TotalTransfer), slog.Uint64("change", summary.Change))
+
+	return mneeTransaction, &summary, addressToSigner, nil
+}
+
+// transferSettlement is the fee and change of a transfer spending a set of inputs.
+type transferSettlement struct {
+	inputs        []MneeTxo
+	totalInput    uint64
+	fee           Fee
+	change        uint64
+	changeAddress string
+}
+
+// selectionTarget returns the target to select inputs for after `selected`
+// failed to settle: the transfer amount plus the fee the selection pays, or,
+// when that does not exceed the failed target, plus the highest fee tier, which
+// covers change priced into a higher tier. It returns `target` when no higher
+// target applies.
+func (b *TransferBuilder) selectionTarget(selected []MneeTxo, outputs []TransferOutput, totalTransfer uint64,
+	target uint64, config *SystemConfig) uint64 {
+
+	var inputAddresses []string = make([]string, 0)
+	for _, txo := range selected {
+		if !slices.Contains(inputAddresses, txo.Owners[0]) {
+			inputAddresses = append(inputAddresses, txo.Owners[0])
+		}
+	}
+
+	if fee, ok := b.feePolicy(config.Fees, feeBase(outputs, inputAddresses, *config.FeeAddress)); ok && totalTransfer+fee.Fee > target {
+		return totalTransfer + fee.Fee
+	}
+
+	var maxFee uint64
+	for _, fee := range config.Fees {
+		maxFee = max(maxFee, fee.Fee)
+	}
+
+	return max(target, totalTransfer+maxFee)
+}
+
+// settle prices a transfer spending `inputs` into `outputs`. The change goes to
+// the address of changeFunc or the configured change address, or to the owner
+// of the last input. It reports false when the inputs do not cover the transfer
+// and its fee.
+func (b *TransferBuilder) settle(ctx context.Context, inputs []MneeTxo, outputs []TransferOutput, totalTransfer uint64,
+	config *SystemConfig, changeFunc ChangeAddressFunc) (transferSettlement, bool, error) {
+
+	var settlement transferSettlement = transferSettlement{inputs: inputs}
+	var inputAddresses []string = make([]string, 0)
+	for _, txo := range inputs {
+		settlement.totalInput += txo.Data.Bsv21.Amt
+		if !slices.Contains(inputAddresses, txo.Owners[0]) {
+			inputAddresses = append(inputAddresses, txo.Owners[0])
+		}
+	}
+
+	if settlement.totalInput < totalTransfer {
+		return settlement, false, nil
+	}
+
+	var available uint64 = settlement.totalInput - totalTransfer
+	settlement.changeAddress = inputs[len(inputs)-1].Owners[0]
+
+	fee, ok := b.changeFee(config, outputs, inputAddresses, settlement.changeAddress, available)
+	if ok && available > fee.Fee && (b.changeAddress != "" || changeFunc != nil) {
+		var changeAddress string = b.changeAddress
+		if changeFunc != nil {
+			var err error
+			changeAddress, err = changeFunc(ctx, inputs)
+			if err != nil {
+				return settlement, false, err
+			}
+		}
+
+		if changeAddress != "" {
+			settlement.changeAddress = changeAddress
+			fee, ok = b.changeFee(config, outputs, inputAddresses, changeAddress, available)
+		}
+	}
+
+	if !ok {
+		return settlement, false, nil
+	}
+
+	settlement.fee = fee
+	settlement.change = available - fee.Fee
+	return settlement, true, nil
+}
+
+// changeFee returns the fee of a transfer leaving `available` above the
+// recipients' amount, the rest going to changeAddress as change. Change sent
+// outside the input addresses is part of the fee base, so the fee is solved
+// until it matches the tier of the change it leaves. It reports false when
+// `available` does not cover the fee or no fee matches its own change.
+func (b *TransferBuilder) changeFee(config *SystemConfig, outputs []TransferOutput, inputAddresses []string,
+	changeAddress string, available uint64) (Fee, bool) {
+
+	fee, ok := b.feePolicy(config.Fees, feeBase(outputs, inputAddresses, *config.FeeAddress))
+	for range len(config.Fees) + 1 {
+		if !ok || fee.Fee > available {
+			return Fee{}, false
+		}
+
+		var withChange []TransferOutput = append(slices.Clip(outputs),
+			TransferOutput{Kind: OutputChange, Address: changeAddress, Amount: available - fee.Fee})
+		next, nextOk := b.feePolicy(config.Fees, feeBase(withChange, inputAddresses, *config.FeeAddress))
+		if nextOk && next.Fee == fee.Fee {
+			return next, true
+		}
+
+		fee, ok = next, nextOk
+	}
+
+	return Fee{}, false
+}
+
+// splitChange divides the change into at most `count` non-zero amounts,
+// giving the remainder to the first one.
+func splitChange(change uint64, count int) []uint64 {
+
+	var outputs uint64 = uint64(max(count, 1))
+	if outputs > change {
+		outputs = change
+	}
+
+	var amounts []uint64 = make([]uint64, outputs)
+	for i := range amounts {
+		amounts[i] = change / outputs
+	}
+	amounts[0] += change % outputs
+
+	return amounts
+}
+
+// addTransferOutput appends a MNEE transfer inscription of `amount` locked to `address`.
+func addTransferOutput(mneeTransaction *transaction.Transaction, address string, amount uint64,
+	approverPubKey *primitives.PublicKey, tokenID string) error {
+
+	scriptAddress, err := script.NewAddressFromString(address)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidAddress, address, err)
+	}
+
+	lockingScript, err := lock(scriptAddress, approverPubKey)
+	if err != nil {
+		return err
+	}
+
+	transferInscription, err := createTransferInscription(tokenID, amount)
+	if err != nil {
+		return err
+	}
+
+	return mneeTransaction.Inscribe(&script.InscriptionArgs{
+		ContentType:   "application/bsv-20",
+		Data:          transferInscription,
+		LockingScript: lockingScript,
+	})
+}
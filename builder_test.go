@@ -0,0 +1,376 @@
+package mnee
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	privateKey *primitives.PrivateKey
+	wif        string
+	address    string
+}
+
+func newTestKey(t *testing.T) testKey {
+	privateKey, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	require.NoError(t, err)
+
+	return testKey{privateKey: privateKey, wif: privateKey.Wif(), address: address.AddressString}
+}
+
+type testFixture struct {
+	mnee     *MNEE
+	server   *httptest.Server
+	config   SystemConfig
+	approver *primitives.PrivateKey
+	txos     []MneeTxo
+	// history is served by /v1/sync after an entry receiving each UTXO.
+	history []TransactionHistoryDTO
+	// syncRequests counts the history pages served by /v1/sync.
+	syncRequests atomic.Int32
+
+	// submitted records the outpoints spent through /v1/transfer, which echoes the rawtx back.
+	mutex     sync.Mutex
+	submitted map[string]bool
+}
+
+// newTestFixture serves a fixed system config, the given UTXOs and their balances over httptest.
+func newTestFixture(t *testing.T, txos []MneeTxo) *testFixture {
+	approver, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+
+	approverHex := hex.EncodeToString(approver.PubKey().Compressed())
+	feeAddress := newTestKey(t).address
+	tokenID := "ae59f3b898ec61acbdb6cc7a245fabeded0c094bf046f35206a3aec60ef88127_0"
+
+	fixture := &testFixture{
+		approver:  approver,
+		txos:      txos,
+		submitted: make(map[string]bool),
+		config: SystemConfig{
+			Decimals:   5,
+			Approver:   &approverHex,
+			FeeAddress: &feeAddress,
+			TokenId:    &tokenID,
+			Fees: []Fee{
+				{MinAmt: 0, MaxAmt: 1000000, Fee: 100},
+				{MinAmt: 1000001, MaxAmt: math.MaxUint64, Fee: 1000},
+			},
+		},
+	}
+
+	fixture.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/config":
+			_ = json.NewEncoder(w).Encode(fixture.config)
+		case "/v1/utxos":
+			_ = json.NewEncoder(w).Encode(fixture.txos)
+		case "/v2/balance":
+			var addresses []string
+			_ = json.NewDecoder(r.Body).Decode(&addresses)
+			balances := make([]BalanceDataDTO, 0, len(addresses))
+			for _, address := range addresses {
+				balance := BalanceDataDTO{Address: &address}
+				for _, txo := range fixture.txos {
+					if txo.Owners[0] == address {
+						balance.Amt += float64(txoAmount(txo))
+					}
+				}
+				balance.Precised = balance.Amt / 1e5
+				balances = append(balances, balance)
+			}
+			_ = json.NewEncoder(w).Encode(balances)
+		case "/v1/sync":
+			fixture.syncRequests.Add(1)
+			var addresses []string
+			_ = json.NewDecoder(r.Body).Decode(&addresses)
+			history := make([]TransactionHistoryDTO, 0)
+			for _, txo := range fixture.txos {
+				history = append(history, TransactionHistoryDTO{Txid: txo.Txid, Receivers: txo.Owners})
+			}
+			history = append(history, fixture.history...)
+			matching := make([]TransactionHistoryDTO, 0)
+			for _, entry := range history {
+				if slices.ContainsFunc(addresses, func(address string) bool {
+					return slices.Contains(entry.Senders, address) || slices.Contains(entry.Receivers, address)
+				}) {
+					matching = append(matching, entry)
+				}
+			}
+			from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			from = min(from, len(matching))
+			_ = json.NewEncoder(w).Encode(matching[from:min(from+limit, len(matching))])
+		case "/v1/transfer":
+			var request TransferRequestDTO
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			txBytes, _ := base64.StdEncoding.DecodeString(request.RawTx)
+			submitted, err := transaction.NewTransactionFromBytes(txBytes)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fixture.mutex.Lock()
+			defer fixture.mutex.Unlock()
+			for _, input := range submitted.Inputs {
+				if fixture.submitted[fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)] {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"message":"input already spent"}`))
+					return
+				}
+			}
+			for _, input := range submitted.Inputs {
+				fixture.submitted[fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)] = true
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"rawtx": request.RawTx})
+		default:
+			for _, txo := range fixture.txos {
+				if r.URL.Path == "/v2/txos/"+*txo.Outpoint {
+					_ = json.NewEncoder(w).Encode(txo)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(fixture.server.Close)
+
+	fixture.mnee, err = NewMneeInstance(EnvCustom, "token", WithBaseURL(fixture.server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+
+	return fixture
+}
+
+// newTestTxo returns a MNEE UTXO of `amount` owned by `address`.
+func newTestTxo(t *testing.T, address string, amount uint64, height uint64) MneeTxo {
+	scriptAddress, err := script.NewAddressFromString(address)
+	require.NoError(t, err)
+
+	lockingScript, err := lock(scriptAddress, newTestKey(t).privateKey.PubKey())
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s-%d-%d", address, amount, height))
+	txid := hex.EncodeToString(hash[:])
+	outpoint := txid + "_0"
+	encodedScript := base64.StdEncoding.EncodeToString(lockingScript.Bytes())
+
+	return MneeTxo{
+		Satoshis: 1,
+		Height:   height,
+		Txid:     &txid,
+		Outpoint: &outpoint,
+		Script:   &encodedScript,
+		Owners:   []string{address},
+		Data:     &Data{Bsv21: &BsvData{Amt: amount, Decimals: 5}},
+	}
+}
+
+func TestTransferBuilder_Build(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+		newTestTxo(t, recipient.address, 9000, 3),
+	})
+
+	t.Log("Test Case 1: Unsigned build selects inputs, fee and change...")
+	mneeTransaction, summary, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 4000).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Len(summary.Inputs, 2, "Only the sender's UTXOs should be selected")
+	assertions.Equal(uint64(8000), summary.TotalInput)
+	assertions.Equal(uint64(100), summary.Fee)
+	assertions.Equal(uint64(3900), summary.Change)
+	assertions.Equal(sender.address, summary.ChangeAddress)
+	assertions.Equal([]TransferOutput{
+		{Kind: OutputRecipient, Address: recipient.address, Amount: 4000},
+		{Kind: OutputFee, Address: *fixture.config.FeeAddress, Amount: 100},
+		{Kind: OutputChange, Address: sender.address, Amount: 3900},
+	}, summary.Outputs)
+	assertions.Len(mneeTransaction.Outputs, 3)
+	assertions.Nil(mneeTransaction.Inputs[0].UnlockingScript, "Build must not sign")
+
+	t.Log("Test Case 2: Change address override...")
+	changeKey := newTestKey(t)
+	_, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 4000).
+		WithChangeAddress(changeKey.address).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(changeKey.address, summary.ChangeAddress)
+
+	t.Log("Test Case 3: A fee policy can waive the zero-amount tier of self transfers...")
+	_, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(sender.address, 3000).
+		WithFeePolicy(func(fees []Fee, amount uint64) (Fee, bool) {
+			if amount == 0 {
+				return Fee{}, true
+			}
+			return TieredFeePolicy(fees, amount)
+		}).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(0), summary.Fee)
+	assertions.Len(summary.Outputs, 1, "No fee or change output is needed")
+
+	t.Log("Test Case 4: Insufficient balance and zero amounts are rejected...")
+	_, _, err = fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 7950).Build(context.Background())
+	assertions.ErrorIs(err, ErrInsufficientMneeBalance)
+	_, _, err = fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 0).Build(context.Background())
+	assertions.ErrorIs(err, ErrTransferAmountGreaterThan0)
+}
+
+func TestTransferBuilder_SignMatchesPartialSign(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+	})
+	transferDTOs := []TransferMneeDTO{{Address: recipient.address, Amount: 4000}}
+
+	mneeTransaction, _, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipients(transferDTOs...).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	for _, input := range mneeTransaction.Inputs {
+		assertions.NotNil(input.UnlockingScript, "Every owned input should be signed")
+	}
+
+	partialHex, err := fixture.mnee.PartialSign(context.Background(), []string{sender.wif}, transferDTOs, false, nil)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(mneeTransaction.Hex(), *partialHex, "PartialSign should produce the builder's transaction")
+
+	withTxosHex, err := fixture.mnee.PartialSign(context.Background(), []string{sender.wif}, transferDTOs, true, fixture.txos)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(*partialHex, *withTxosHex)
+}
+
+func TestTransferBuilder_ChangeRouting(t *testing.T) {
+	assertions := assert.New(t)
+
+	first := newTestKey(t)
+	second := newTestKey(t)
+	recipient := newTestKey(t)
+	hotWallet := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, first.address, 3000, 1),
+		newTestTxo(t, second.address, 5000, 2),
+	})
+
+	t.Log("Test Case 1: A callback routes the change and it is split in three...")
+	type contextKey struct{}
+	var callbackInputs []MneeTxo
+	var callbackContext context.Context
+	_, summary, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(first.wif, second.wif).
+		AddRecipient(recipient.address, 4000).
+		WithChangeAddressFunc(func(ctx context.Context, inputs []MneeTxo) (string, error) {
+			callbackInputs, callbackContext = inputs, ctx
+			return hotWallet.address, nil
+		}).
+		WithChangeOutputs(3).
+		Build(context.WithValue(context.Background(), contextKey{}, "build"))
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Len(callbackInputs, 2)
+	assertions.Equal("build", callbackContext.Value(contextKey{}), "The callback receives the build context")
+	assertions.Equal(hotWallet.address, summary.ChangeAddress)
+	assertions.Equal(uint64(3900), summary.Change)
+	assertions.Equal([]TransferOutput{
+		{Kind: OutputRecipient, Address: recipient.address, Amount: 4000},
+		{Kind: OutputFee, Address: *fixture.config.FeeAddress, Amount: 100},
+		{Kind: OutputChange, Address: hotWallet.address, Amount: 1300},
+		{Kind: OutputChange, Address: hotWallet.address, Amount: 1300},
+		{Kind: OutputChange, Address: hotWallet.address, Amount: 1300},
+	}, summary.Outputs)
+
+	t.Log("Test Case 2: Callback errors abort the build...")
+	_, _, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(first.wif, second.wif).
+		AddRecipient(recipient.address, 4000).
+		WithChangeAddressFunc(func(ctx context.Context, inputs []MneeTxo) (string, error) {
+			return "", ErrInvalidConfig
+		}).
+		Build(context.Background())
+	assertions.ErrorIs(err, ErrInvalidConfig)
+
+	t.Log("Test Case 3: Change sent outside the inputs is priced like a recipient and validates...")
+	whale := newTestKey(t)
+	whaleFixture := newTestFixture(t, []MneeTxo{newTestTxo(t, whale.address, 5000000, 1)})
+	for _, changeAddress := range []string{hotWallet.address, whale.address} {
+		mneeTransaction, summary, err := whaleFixture.mnee.NewTransferBuilder().
+			WithWIFs(whale.wif).
+			AddRecipient(recipient.address, 4000).
+			WithChangeAddress(changeAddress).
+			Sign(context.Background())
+		if !assertions.NoError(err) {
+			return
+		}
+
+		violations, err := whaleFixture.mnee.ValidateTransaction(context.Background(), mneeTransaction.Hex(), summary.Inputs)
+		assertions.NoError(err)
+		assertions.Empty(violations, changeAddress)
+		assertions.Equal(uint64(5000000), summary.TotalTransfer+summary.Fee+summary.Change)
+	}
+
+	_, summary, err = whaleFixture.mnee.NewTransferBuilder().
+		WithWIFs(whale.wif).
+		AddRecipient(recipient.address, 4000).
+		WithChangeAddress(hotWallet.address).
+		Build(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal(uint64(1000), summary.Fee)
+		assertions.Equal(uint64(4995000), summary.Change)
+	}
+
+	t.Log("Test Case 4: Change split never creates empty outputs...")
+	assertions.Equal([]uint64{4, 3, 3}, splitChange(10, 3))
+	assertions.Equal([]uint64{1, 1}, splitChange(2, 5))
+	assertions.Equal([]uint64{7}, splitChange(7, 0))
+}
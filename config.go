@@ -3,14 +3,15 @@ package mnee
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 )
 
 // GetConfig fetches the current MNEE system configuration from the API.
 // It caches the config internally for 1 hour to reduce API calls.
-func (m *MNEE) GetConfig(ctx context.Context) (*SystemConfig, error) {
+func (m *MNEE) GetConfig(ctx context.Context) (_ *SystemConfig, err error) {
+
+	ctx, span := m.startSpan(ctx, "GetConfig")
+	defer func() { span.End(err) }()
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -31,42 +32,17 @@ func (m *MNEE) GetConfig(ctx context.Context) (*SystemConfig, error) {
 		}
 	}
 
-	configRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		(m.mneeURL + "/v1/config?auth_token=" + m.mneeToken),
-		nil,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	configResponse, err := m.httpClient.Do(configRequest)
+	configResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodGet,
+		endpoint:   "/v1/config",
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	defer configResponse.Body.Close()
 
-	if configResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if configResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(configResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", configResponse.StatusCode)
-		}
-
-		return nil, errors.New(errorMessage)
-	}
-
 	var systemConfig SystemConfig
 	err = json.NewDecoder(configResponse.Body).Decode(&systemConfig)
 	if err != nil {
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+func main() {
+	apiKey := os.Getenv("MNEE_API_KEY")
+	testAddress := os.Getenv("MNEE_TEST_ADDRESS") // Address holding funds, no key needed
+	recipientAddress := os.Getenv("MNEE_RECIPIENT_ADDRESS")
+	if apiKey == "" || testAddress == "" || recipientAddress == "" {
+		log.Fatal("MNEE_API_KEY, MNEE_TEST_ADDRESS, and MNEE_RECIPIENT_ADDRESS env vars must be set")
+	}
+
+	m, err := mnee.NewMneeInstance(mnee.EnvSandbox, apiKey)
+	if err != nil {
+		log.Fatalf("Error creating MNEE instance: %v", err)
+	}
+
+	fmt.Println("Quoting transfer...")
+	transferDTOs := []mnee.TransferMneeDTO{
+		{Amount: 1000, Address: recipientAddress},
+	}
+
+	quote, err := m.QuoteTransfer(context.Background(), []string{testAddress}, transferDTOs)
+	if err != nil {
+		log.Fatalf("Error quoting transfer: %v", err)
+	}
+
+	fmt.Printf("Fee: %d (tier %d-%d)\n", quote.Fee, quote.FeeTier.MinAmt, quote.FeeTier.MaxAmt)
+	fmt.Printf("Inputs consumed: %d\n", len(quote.Inputs))
+	for _, output := range quote.Outputs {
+		fmt.Printf(" - %s: %d -> %s\n", output.Kind, output.Amount, output.Address)
+	}
+	fmt.Printf("Change: %d\n", quote.Change)
+	fmt.Printf("Total debited: %d\n", quote.TotalDebited)
+	fmt.Printf("Self transfer (fee exempt): %t\n", quote.SelfTransfer)
+}
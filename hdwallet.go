@@ -0,0 +1,380 @@
+package mnee
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	bip32 "github.com/bsv-blockchain/go-sdk/compat/bip32"
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	chaincfg "github.com/bsv-blockchain/go-sdk/transaction/chaincfg"
+)
+
+const (
+	// HDPurpose is the BIP44 purpose level of every derived path.
+	HDPurpose uint32 = 44
+	// HDCoinType is the SLIP-44 coin type of Bitcoin SV, under which MNEE keys are derived.
+	HDCoinType uint32 = 236
+	// HDChainReceive is the external chain, used for addresses handed out to payers.
+	HDChainReceive uint32 = 0
+	// HDChainChange is the internal chain, used for change outputs.
+	HDChainChange uint32 = 1
+	// DefaultGapLimit is the number of consecutive unused addresses after which discovery stops.
+	DefaultGapLimit uint32 = 20
+)
+
+// HDAddress is an address derived from an HDWallet account.
+type HDAddress struct {
+	Address string `json:"address"`
+	Chain   uint32 `json:"chain"`
+	Index   uint32 `json:"index"`
+	// Path is the full derivation path, e.g. m/44'/236'/0'/0/3.
+	Path string `json:"path"`
+}
+
+// HDWalletOption configures an HDWallet.
+type HDWalletOption func(*HDWallet) error
+
+// WithGapLimit sets how many consecutive unused addresses Discover scans
+// before it considers a chain exhausted. It defaults to DefaultGapLimit.
+func WithGapLimit(gapLimit uint32) HDWalletOption {
+	return func(w *HDWallet) error {
+		if gapLimit == 0 {
+			return ErrInvalidGapLimit
+		}
+
+		w.gapLimit = gapLimit
+		return nil
+	}
+}
+
+// HDWallet is a BIP32/BIP44 account (m/44'/236'/account') whose receive and
+// change addresses are discovered against the MNEE API. It implements Signer
+// for every address up to the discovered frontier of both chains, so a whole
+// account can be spent from with TransferBuilder.WithSigner.
+type HDWallet struct {
+	mnee         *MNEE
+	accountKey   *bip32.ExtendedKey
+	accountIndex uint32
+	gapLimit     uint32
+
+	mutex sync.Mutex
+	// next holds, per chain, the index of the first address after the last used one.
+	next [2]uint32
+	keys map[string]*primitives.PrivateKey
+}
+
+// NewHDWallet opens account `account` of the BIP32 master key. The master
+// key must be private; use bip32.NewMaster or bip32.GenerateHDKeyFromMnemonic
+// to obtain one. Call Discover before spending to find the used addresses.
+func (m *MNEE) NewHDWallet(masterKey *bip32.ExtendedKey, account uint32, options ...HDWalletOption) (*HDWallet, error) {
+
+	if masterKey == nil || !masterKey.IsPrivate() {
+		return nil, ErrHDKeyNotPrivate
+	}
+
+	var accountKey *bip32.ExtendedKey = masterKey
+	for _, index := range []uint32{HDPurpose, HDCoinType, account} {
+		var err error
+		accountKey, err = accountKey.Child(bip32.HardenedKeyStart + index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var wallet HDWallet = HDWallet{
+		mnee:         m,
+		accountKey:   accountKey,
+		accountIndex: account,
+		gapLimit:     DefaultGapLimit,
+		keys:         make(map[string]*primitives.PrivateKey),
+	}
+
+	for _, option := range options {
+		err := option(&wallet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &wallet, nil
+}
+
+// NewHDWalletFromSeed opens account `account` of the master key derived from a BIP32 seed.
+func (m *MNEE) NewHDWalletFromSeed(seed []byte, account uint32, options ...HDWalletOption) (*HDWallet, error) {
+
+	masterKey, err := bip32.NewMaster(seed, &chaincfg.MainNet)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.NewHDWallet(masterKey, account, options...)
+}
+
+// DeriveAddress derives the address at `index` of `chain` (HDChainReceive or HDChainChange).
+func (w *HDWallet) DeriveAddress(chain uint32, index uint32) (HDAddress, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.derive(chain, index)
+}
+
+// Discover scans both chains against the transaction history in windows of the
+// gap limit and stops each chain after gap-limit consecutive addresses that
+// never took part in a MNEE transaction. Addresses that were paid and later
+// emptied count as used. It returns the addresses found to be in use.
+func (w *HDWallet) Discover(ctx context.Context) (_ []HDAddress, err error) {
+
+	ctx, span := w.mnee.startSpan(ctx, "HDWallet.Discover")
+	defer func() { span.End(err) }()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var used []HDAddress = make([]HDAddress, 0)
+	for _, chain := range []uint32{HDChainReceive, HDChainChange} {
+		var index uint32 = 0
+		var gap uint32 = 0
+		var next uint32 = 0
+
+		for gap < w.gapLimit {
+			var window []HDAddress = make([]HDAddress, 0, w.gapLimit)
+			var addresses []string = make([]string, 0, w.gapLimit)
+			for offset := range w.gapLimit {
+				hdAddress, err := w.derive(chain, index+offset)
+				if err != nil {
+					return nil, err
+				}
+
+				window = append(window, hdAddress)
+				addresses = append(addresses, hdAddress.Address)
+			}
+
+			inUse, err := w.usedAddresses(ctx, addresses)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, hdAddress := range window {
+				if !inUse[hdAddress.Address] {
+					gap++
+					continue
+				}
+
+				gap = 0
+				next = hdAddress.Index + 1
+				used = append(used, hdAddress)
+			}
+
+			index += w.gapLimit
+		}
+
+		w.next[chain] = next
+	}
+
+	span.SetAttributes(Attribute{Key: "mnee.address_count", Value: len(used)})
+	return used, nil
+}
+
+// NextReceiveAddress returns the first receive address after the last used
+// one. The address is re-checked against the transaction history and skipped
+// if it has been used since the last call, so it is never handed out twice once paid.
+func (w *HDWallet) NextReceiveAddress(ctx context.Context) (_ HDAddress, err error) {
+
+	ctx, span := w.mnee.startSpan(ctx, "HDWallet.NextReceiveAddress")
+	defer func() { span.End(err) }()
+
+	return w.nextAddress(ctx, HDChainReceive)
+}
+
+// NextChangeAddress returns the first unused change address.
+func (w *HDWallet) NextChangeAddress(ctx context.Context) (_ HDAddress, err error) {
+
+	ctx, span := w.mnee.startSpan(ctx, "HDWallet.NextChangeAddress")
+	defer func() { span.End(err) }()
+
+	return w.nextAddress(ctx, HDChainChange)
+}
+
+// WIFs returns the private keys of every address up to the discovered
+// frontier, for use with SynchronousTransfer, AsynchronousTransfer and PartialSign.
+func (w *HDWallet) WIFs() []string {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var wifs []string = make([]string, 0)
+	for _, address := range w.addresses() {
+		wifs = append(wifs, w.keys[address].Wif())
+	}
+
+	return wifs
+}
+
+// NewTransferBuilder returns a TransferBuilder that spends from the whole
+// account and sends change to the next unused change address, looked up once
+// per build.
+//
+// The change address is never one of the inputs, so the cosigner counts the
+// change toward the fee tier like a recipient: a small payment from a large
+// UTXO pays the tier of the payment plus its change. Call WithChangeAddress
+// with an input address on the returned builder to avoid that.
+func (w *HDWallet) NewTransferBuilder() *TransferBuilder {
+
+	var builder *TransferBuilder = w.mnee.NewTransferBuilder().WithSigner(w)
+	builder.changeResolver = func(ctx context.Context) (string, error) {
+		hdAddress, err := w.NextChangeAddress(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return hdAddress.Address, nil
+	}
+
+	return builder
+}
+
+// Addresses implements Signer. It lists every receive and change address
+// below the discovered frontier, including unused addresses inside a gap.
+func (w *HDWallet) Addresses(ctx context.Context) ([]string, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.addresses(), nil
+}
+
+// PublicKey implements Signer.
+func (w *HDWallet) PublicKey(ctx context.Context, address string) (*primitives.PublicKey, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	privateKey, ok := w.keys[address]
+	if !ok {
+		return nil, ErrUnknownSignerAddress
+	}
+
+	return privateKey.PubKey(), nil
+}
+
+// Sign implements Signer.
+func (w *HDWallet) Sign(ctx context.Context, address string, sighash []byte) (*primitives.Signature, error) {
+
+	w.mutex.Lock()
+	privateKey, ok := w.keys[address]
+	w.mutex.Unlock()
+
+	if !ok {
+		return nil, ErrUnknownSignerAddress
+	}
+
+	return privateKey.Sign(sighash)
+}
+
+// nextAddress returns the address at the frontier of `chain`, advancing past
+// addresses that have received funds since discovery.
+func (w *HDWallet) nextAddress(ctx context.Context, chain uint32) (HDAddress, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for {
+		hdAddress, err := w.derive(chain, w.next[chain])
+		if err != nil {
+			return HDAddress{}, err
+		}
+
+		inUse, err := w.usedAddresses(ctx, []string{hdAddress.Address})
+		if err != nil {
+			return HDAddress{}, err
+		}
+
+		if !inUse[hdAddress.Address] {
+			return hdAddress, nil
+		}
+
+		w.next[chain]++
+	}
+}
+
+// usedAddresses returns the subset of `addresses` that appear in the MNEE
+// transaction history as a sender or receiver. Unlike a balance check, it
+// also finds addresses whose funds were spent. Each address is looked up with
+// a single one-entry history page, so the cost does not grow with the history.
+func (w *HDWallet) usedAddresses(ctx context.Context, addresses []string) (map[string]bool, error) {
+
+	var used map[string]bool = make(map[string]bool)
+	for _, address := range addresses {
+		history, err := w.mnee.GetSpecificTransactionHistory(ctx, []string{address}, 0, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(history) > 0 {
+			used[address] = true
+		}
+	}
+
+	return used, nil
+}
+
+// addresses lists the addresses below the frontier of both chains, deriving them if needed.
+func (w *HDWallet) addresses() []string {
+
+	var addresses []string = make([]string, 0, w.next[HDChainReceive]+w.next[HDChainChange])
+	for _, chain := range []uint32{HDChainReceive, HDChainChange} {
+		for index := range w.next[chain] {
+			hdAddress, err := w.derive(chain, index)
+			if err != nil {
+				continue
+			}
+
+			addresses = append(addresses, hdAddress.Address)
+		}
+	}
+
+	return addresses
+}
+
+// derive derives and caches the key at `chain`/`index` of the account. The
+// caller must hold the mutex.
+func (w *HDWallet) derive(chain uint32, index uint32) (HDAddress, error) {
+
+	if chain != HDChainReceive && chain != HDChainChange {
+		return HDAddress{}, ErrInvalidHDChain
+	}
+
+	chainKey, err := w.accountKey.Child(chain)
+	if err != nil {
+		return HDAddress{}, err
+	}
+
+	indexKey, err := chainKey.Child(index)
+	if err != nil {
+		return HDAddress{}, err
+	}
+
+	privateKey, err := indexKey.ECPrivKey()
+	if err != nil {
+		return HDAddress{}, err
+	}
+
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		return HDAddress{}, err
+	}
+
+	var hdAddress HDAddress = HDAddress{
+		Address: address.AddressString,
+		Chain:   chain,
+		Index:   index,
+		Path:    fmt.Sprintf("m/%d'/%d'/%d'/%d/%d", HDPurpose, HDCoinType, w.accountIndex, chain, index),
+	}
+
+	w.keys[hdAddress.Address] = privateKey
+
+	return hdAddress, nil
+}
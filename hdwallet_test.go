@@ -0,0 +1,116 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHDWallet_Discover(t *testing.T) {
+	assertions := assert.New(t)
+
+	fixture := newTestFixture(t, nil)
+	wallet, err := fixture.mnee.NewHDWalletFromSeed(bytes.Repeat([]byte{0x42}, 32), 0, WithGapLimit(2))
+	require.NoError(t, err)
+
+	derive := func(chain uint32, index uint32) HDAddress {
+		hdAddress, err := wallet.DeriveAddress(chain, index)
+		require.NoError(t, err)
+		return hdAddress
+	}
+
+	t.Log("Test Case 1: Paths follow BIP44 for the MNEE coin type...")
+	assertions.Equal("m/44'/236'/0'/1/7", derive(HDChainChange, 7).Path)
+	_, err = wallet.DeriveAddress(2, 0)
+	assertions.ErrorIs(err, ErrInvalidHDChain)
+
+	recipient := newTestKey(t)
+	fixture.txos = []MneeTxo{
+		newTestTxo(t, derive(HDChainReceive, 0).Address, 3000, 1),
+		newTestTxo(t, derive(HDChainReceive, 3).Address, 2000, 2),
+		newTestTxo(t, derive(HDChainReceive, 6).Address, 9000, 3),
+		newTestTxo(t, derive(HDChainChange, 1).Address, 1000, 4),
+	}
+
+	// Change index 0 was paid and then emptied: it has history but no balance.
+	fixture.history = []TransactionHistoryDTO{
+		{Receivers: []string{derive(HDChainChange, 0).Address}},
+		{Senders: []string{derive(HDChainChange, 0).Address}, Receivers: []string{recipient.address}},
+	}
+
+	t.Log("Test Case 2: Discovery stops after the gap limit...")
+	used, err := wallet.Discover(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal([]HDAddress{derive(HDChainReceive, 0), derive(HDChainReceive, 3), derive(HDChainChange, 0), derive(HDChainChange, 1)}, used,
+		"Receive index 6 lies beyond a gap of two unused addresses; emptied change index 0 is used")
+
+	addresses, err := wallet.Addresses(context.Background())
+	assertions.NoError(err)
+	assertions.Len(addresses, 6, "Four receive and two change addresses sit below the frontier")
+	assertions.Len(wallet.WIFs(), 6)
+
+	nextReceive, err := wallet.NextReceiveAddress(context.Background())
+	assertions.NoError(err)
+	assertions.Equal(derive(HDChainReceive, 4), nextReceive)
+
+	t.Log("Test Case 3: A receive address funded after discovery is skipped...")
+	fixture.txos = append(fixture.txos, newTestTxo(t, nextReceive.Address, 500, 5))
+	nextReceive, err = wallet.NextReceiveAddress(context.Background())
+	assertions.NoError(err)
+	assertions.Equal(derive(HDChainReceive, 5), nextReceive)
+
+	t.Log("Test Case 4: The account spends across addresses and routes change to the change chain...")
+	fixture.syncRequests.Store(0)
+	mneeTransaction, summary, err := wallet.NewTransferBuilder().
+		AddRecipient(recipient.address, 5500).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(derive(HDChainChange, 2).Address, summary.ChangeAddress)
+	assertions.Equal(uint64(5500+100), summary.TotalInput-summary.Change)
+	for _, input := range mneeTransaction.Inputs {
+		assertions.NotNil(input.UnlockingScript, "Every discovered input should be signed")
+	}
+	assertions.Equal(int32(1), fixture.syncRequests.Load(), "The change address is looked up once per build")
+
+	t.Log("Test Case 5: Change to the change chain counts toward the fee tier...")
+	whaleFixture := newTestFixture(t, nil)
+	whaleWallet, err := whaleFixture.mnee.NewHDWalletFromSeed(bytes.Repeat([]byte{0x43}, 32), 0)
+	require.NoError(t, err)
+	whaleReceive, err := whaleWallet.DeriveAddress(HDChainReceive, 0)
+	require.NoError(t, err)
+	whaleTxo := newTestTxo(t, whaleReceive.Address, 5000000, 1)
+	whaleFixture.txos = []MneeTxo{whaleTxo}
+	_, err = whaleWallet.Discover(context.Background())
+	require.NoError(t, err)
+
+	mneeTransaction, summary, err = whaleWallet.NewTransferBuilder().
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal(uint64(1000), summary.Fee, "4000 plus 4,995,000 of change falls in the upper tier")
+		violations, err := whaleFixture.mnee.ValidateTransaction(context.Background(), mneeTransaction.Hex(), []MneeTxo{whaleTxo})
+		assertions.NoError(err)
+		assertions.Empty(violations)
+	}
+
+	_, summary, err = whaleWallet.NewTransferBuilder().
+		AddRecipient(recipient.address, 4000).
+		WithChangeAddress(whaleReceive.Address).
+		Build(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal(uint64(100), summary.Fee, "Change back to the input address is exempt")
+	}
+
+	t.Log("Test Case 6: Invalid options are rejected...")
+	_, err = fixture.mnee.NewHDWalletFromSeed(bytes.Repeat([]byte{0x42}, 32), 0, WithGapLimit(0))
+	assertions.ErrorIs(err, ErrInvalidGapLimit)
+	_, err = fixture.mnee.NewHDWallet(nil, 0)
+	assertions.ErrorIs(err, ErrHDKeyNotPrivate)
+}
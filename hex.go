@@ -5,52 +5,33 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 )
 
 // GetMNEETxHex fetches a MNEE transaction by its TXID and returns its full hex.
-func (m *MNEE) GetMNEETxHex(ctx context.Context, txid string) (*string, error) {
+func (m *MNEE) GetMNEETxHex(ctx context.Context, txid string) (_ *string, err error) {
 
-	mneeHexRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		(m.mneeURL + "/v1/tx/" + txid + "?auth_token=" + m.mneeToken),
-		nil,
-	)
+	ctx, span := m.startSpan(ctx, "GetMNEETxHex", Attribute{Key: "mnee.txid", Value: txid})
+	defer func() { span.End(err) }()
+
+	err = ValidateTxid(txid)
 	if err != nil {
 		return nil, err
 	}
 
-	mneeHexRequest.Header.Set("Content-Type", "application/json")
-
-	mneeHexResponse, err := m.httpClient.Do(mneeHexRequest)
+	mneeHexResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodGet,
+		endpoint:   "/v1/tx",
+		pathParams: []string{txid},
+		headers:    map[string]string{"Content-Type": "application/json"},
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	defer mneeHexResponse.Body.Close()
 
-	if mneeHexResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if mneeHexResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(mneeHexResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", mneeHexResponse.StatusCode)
-		}
-
-		return nil, errors.New(errorMessage)
-	}
-
 	var mneeHexBody struct {
 		RawTx *string `json:"rawtx"`
 	}
@@ -60,7 +41,7 @@ func (m *MNEE) GetMNEETxHex(ctx context.Context, txid string) (*string, error) {
 	}
 
 	if mneeHexBody.RawTx == nil {
-		return nil, errors.New("record not found")
+		return nil, ErrNotFound
 	}
 
 	mneeTxBytes, err := base64.StdEncoding.DecodeString(*mneeHexBody.RawTx)
@@ -0,0 +1,85 @@
+package mnee
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxTicketIDLength bounds the ticket IDs accepted by ValidateTicketID.
+const maxTicketIDLength int = 128
+
+// InvalidIDError is returned, before any request is sent, for a malformed
+// txid, outpoint or ticket ID. It matches ErrInvalidTxid, ErrInvalidOutpoint
+// or ErrInvalidTicketID with errors.Is.
+type InvalidIDError struct {
+	// Err is ErrInvalidTxid, ErrInvalidOutpoint or ErrInvalidTicketID.
+	Err    error
+	Value  string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *InvalidIDError) Error() string {
+	return fmt.Sprintf("%v %q: %s", e.Err, e.Value, e.Reason)
+}
+
+// Unwrap returns the sentinel error of the identifier kind.
+func (e *InvalidIDError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateTxid checks that txid is 64 hex characters.
+func ValidateTxid(txid string) error {
+
+	if len(txid) != 64 {
+		return &InvalidIDError{Err: ErrInvalidTxid, Value: txid, Reason: "must be 64 hex characters"}
+	}
+
+	_, err := hex.DecodeString(txid)
+	if err != nil {
+		return &InvalidIDError{Err: ErrInvalidTxid, Value: txid, Reason: "must be 64 hex characters"}
+	}
+
+	return nil
+}
+
+// ValidateOutpoint checks that outpoint has the "txid_vout" form.
+func ValidateOutpoint(outpoint string) error {
+
+	txid, vout, ok := strings.Cut(outpoint, "_")
+	if !ok {
+		return &InvalidIDError{Err: ErrInvalidOutpoint, Value: outpoint, Reason: `must have the form "txid_vout"`}
+	}
+
+	if ValidateTxid(txid) != nil {
+		return &InvalidIDError{Err: ErrInvalidOutpoint, Value: outpoint, Reason: "txid must be 64 hex characters"}
+	}
+
+	_, err := strconv.ParseUint(vout, 10, 32)
+	if err != nil {
+		return &InvalidIDError{Err: ErrInvalidOutpoint, Value: outpoint, Reason: "vout must be a decimal output index"}
+	}
+
+	return nil
+}
+
+// ValidateTicketID checks that ticketID is a non-empty token of letters,
+// digits, '-', '_' and '.', at most 128 characters long.
+func ValidateTicketID(ticketID string) error {
+
+	if ticketID == "" || len(ticketID) > maxTicketIDLength {
+		return &InvalidIDError{Err: ErrInvalidTicketID, Value: ticketID,
+			Reason: fmt.Sprintf("must be 1 to %d characters", maxTicketIDLength)}
+	}
+
+	for _, char := range ticketID {
+		if !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' ||
+			char == '-' || char == '_' || char == '.') {
+			return &InvalidIDError{Err: ErrInvalidTicketID, Value: ticketID, Reason: fmt.Sprintf("contains %q", char)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package mnee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIdentifiers(t *testing.T) {
+	assertions := assert.New(t)
+	txid := strings.Repeat("ab", 32)
+
+	t.Log("Test Case 1: Well-formed identifiers are accepted...")
+	assertions.NoError(ValidateTxid(txid))
+	assertions.NoError(ValidateTxid(strings.ToUpper(txid)))
+	assertions.NoError(ValidateOutpoint(txid + "_0"))
+	assertions.NoError(ValidateOutpoint(txid + "_4294967295"))
+	assertions.NoError(ValidateTicketID("3f2c9a1e-8b7d-4c2e-9f0a-1b2c3d4e5f60"))
+
+	t.Log("Test Case 2: Malformed txids are rejected...")
+	for _, invalid := range []string{"", "abc", txid + "00", strings.Repeat("zz", 32), txid[:62] + "/."} {
+		err := ValidateTxid(invalid)
+		assertions.ErrorIs(err, ErrInvalidTxid, invalid)
+	}
+
+	t.Log("Test Case 3: Malformed outpoints are rejected...")
+	for _, invalid := range []string{txid, txid + "_", txid + "_-1", txid + "_4294967296", txid + "_0/../x", "abc_0", "../" + txid + "_0"} {
+		err := ValidateOutpoint(invalid)
+		assertions.ErrorIs(err, ErrInvalidOutpoint, invalid)
+	}
+
+	t.Log("Test Case 4: Malformed ticket IDs are rejected...")
+	for _, invalid := range []string{"", "a&auth_token=x", "a b", "a/b", strings.Repeat("a", 129)} {
+		err := ValidateTicketID(invalid)
+		assertions.ErrorIs(err, ErrInvalidTicketID, invalid)
+	}
+
+	var invalidErr *InvalidIDError
+	if assertions.ErrorAs(ValidateOutpoint("abc_0"), &invalidErr) {
+		assertions.Equal("abc_0", invalidErr.Value)
+		assertions.Equal(`invalid outpoint "abc_0": txid must be 64 hex characters`, invalidErr.Error())
+	}
+}
+
+func TestRequestURI(t *testing.T) {
+	assertions := assert.New(t)
+
+	t.Log("Test Case 1: Path parameters and query values are escaped...")
+	call := &apiRequest{
+		endpoint:   "/v2/txos",
+		pathParams: []string{"a/../b c"},
+		query:      url.Values{"ticketID": {"x&y=z"}, "page": {"1"}},
+	}
+	assertions.Equal("/v2/txos/a%2F..%2Fb%20c?page=1&ticketID=x%26y%3Dz", call.requestURI())
+
+	t.Log("Test Case 2: Plain endpoints are unchanged...")
+	assertions.Equal("/v1/config", (&apiRequest{endpoint: "/v1/config"}).requestURI())
+}
+
+func TestInvalidIdentifiers_NoRequest(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL))
+	if !assertions.NoError(err) {
+		return
+	}
+
+	t.Log("Test Case 1: Malformed identifiers fail before any request...")
+	_, err = m.GetMNEETxHex(ctx, "../v1/config")
+	assertions.ErrorIs(err, ErrInvalidTxid)
+	_, err = m.GetTxo(ctx, "not-an-outpoint")
+	assertions.ErrorIs(err, ErrInvalidOutpoint)
+	_, err = m.PollTicket(ctx, "ticket&ticketID=other", 0)
+	assertions.ErrorIs(err, ErrInvalidTicketID)
+	_, err = m.WaitForTicket(ctx, "", WaitOptions{})
+	assertions.ErrorIs(err, ErrInvalidTicketID)
+	assertions.Zero(calls.Load())
+
+	t.Log("Test Case 2: Well-formed identifiers reach the API...")
+	_, err = m.GetTxo(ctx, strings.Repeat("0", 64)+"_1")
+	assertions.ErrorIs(err, ErrNotFound)
+	assertions.Equal(int32(1), calls.Load())
+}
@@ -0,0 +1,121 @@
+package mnee
+
+import (
+	"context"
+	"time"
+)
+
+// Attribute is a key/value pair attached to a span, e.g. an address count or
+// a txid. Values are strings, ints, uint64s or bools.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a unit of work started by Instrumentation.StartSpan.
+type Span interface {
+	// SetAttributes adds attributes known once the work is under way, e.g. the txid.
+	SetAttributes(attrs ...Attribute)
+	// End finishes the span with the method's error, nil on success.
+	End(err error)
+}
+
+// RequestMetric describes one HTTP attempt against the MNEE API.
+type RequestMetric struct {
+	Method string
+	// Endpoint is the API path, without query parameters.
+	Endpoint string
+	// Status is the HTTP status, or 0 when the request failed before a response.
+	Status   int
+	Attempt  int
+	Duration time.Duration
+	Err      error
+}
+
+// TransferMetric describes one transfer submitted by a TransferBuilder.
+type TransferMetric struct {
+	// Mode is "sync" or "async".
+	Mode string
+	// Amount is the total sent to the recipients, in atomic units.
+	Amount uint64
+	Fee    uint64
+	Inputs int
+	Err    error
+}
+
+// Instrumentation receives traces and metrics from the client. Implement it
+// to bridge OpenTelemetry or another telemetry system: StartSpan maps to a
+// tracer, RecordRequest to request latency histograms and error counters by
+// status, RecordTransfer to transfer amount histograms. Implementations must be
+// safe for concurrent use.
+type Instrumentation interface {
+	// StartSpan starts a span named after the public method, e.g. "mnee.GetBalances",
+	// and returns the context carrying it.
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	// RecordRequest is called after every HTTP attempt, retries included.
+	RecordRequest(ctx context.Context, metric RequestMetric)
+	// RecordTransfer is called after every transfer submission.
+	RecordTransfer(ctx context.Context, metric TransferMetric)
+}
+
+// WithInstrumentation reports the spans and metrics of the client to the
+// instrumentation. A nil instrumentation disables it, the default.
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(m *MNEE) error {
+		if instrumentation == nil {
+			instrumentation = NoopInstrumentation{}
+		}
+
+		m.instrumentation = instrumentation
+
+		return nil
+	}
+}
+
+// NoopInstrumentation discards every span and metric.
+type NoopInstrumentation struct{}
+
+// StartSpan returns ctx and a span that does nothing.
+func (NoopInstrumentation) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// RecordRequest does nothing.
+func (NoopInstrumentation) RecordRequest(ctx context.Context, metric RequestMetric) {}
+
+// RecordTransfer does nothing.
+func (NoopInstrumentation) RecordTransfer(ctx context.Context, metric TransferMetric) {}
+
+// noopSpan is the Span of NoopInstrumentation.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+
+func (noopSpan) End(err error) {}
+
+// newTransferMetric describes the submission of a built transfer.
+func newTransferMetric(mode string, summary *TransferSummary, err error) TransferMetric {
+	return TransferMetric{Mode: mode, Amount: summary.TotalTransfer, Fee: summary.Fee, Inputs: len(summary.Inputs), Err: err}
+}
+
+// spanKey is the context key of the innermost span started by the client.
+type spanKey struct{}
+
+// startSpan starts a span for a public method and stores it in the context,
+// so the calls it makes can add attributes to it with spanFromContext.
+func (m *MNEE) startSpan(ctx context.Context, method string, attrs ...Attribute) (context.Context, Span) {
+
+	ctx, span := m.instrumentation.StartSpan(ctx, "mnee."+method, attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanFromContext returns the innermost span started by the client, or a no-op span.
+func spanFromContext(ctx context.Context) Span {
+
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return noopSpan{}
+	}
+
+	return span
+}
@@ -0,0 +1,188 @@
+package mnee_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedSpan is a span kept by recordingInstrumentation.
+type recordedSpan struct {
+	name   string
+	attrs  map[string]any
+	ended  bool
+	err    error
+	parent *recordedSpan
+}
+
+// recordingInstrumentation keeps every span and metric it receives.
+type recordingInstrumentation struct {
+	mutex     sync.Mutex
+	spans     []*recordedSpan
+	requests  []mnee.RequestMetric
+	transfers []mnee.TransferMetric
+}
+
+// parentKey is the context key of the current recordedSpan.
+type parentKey struct{}
+
+func (r *recordingInstrumentation) StartSpan(ctx context.Context, name string, attrs ...mnee.Attribute) (context.Context, mnee.Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	span := &recordedSpan{name: name, attrs: make(map[string]any)}
+	span.parent, _ = ctx.Value(parentKey{}).(*recordedSpan)
+	r.spans = append(r.spans, span)
+	handle := &recordedSpanHandle{span: span}
+	handle.SetAttributes(attrs...)
+	return context.WithValue(ctx, parentKey{}, span), handle
+}
+
+func (r *recordingInstrumentation) RecordRequest(ctx context.Context, metric mnee.RequestMetric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, metric)
+}
+
+func (r *recordingInstrumentation) RecordTransfer(ctx context.Context, metric mnee.TransferMetric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.transfers = append(r.transfers, metric)
+}
+
+// span returns the first span with the name, or nil.
+func (r *recordingInstrumentation) span(name string) *recordedSpan {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, span := range r.spans {
+		if span.name == name {
+			return span
+		}
+	}
+
+	return nil
+}
+
+// recordedSpanHandle is the mnee.Span of a recordedSpan.
+type recordedSpanHandle struct {
+	span *recordedSpan
+}
+
+func (h *recordedSpanHandle) SetAttributes(attrs ...mnee.Attribute) {
+	for _, attr := range attrs {
+		h.span.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (h *recordedSpanHandle) End(err error) {
+	h.span.ended, h.span.err = true, err
+}
+
+func TestWithInstrumentation(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	server, _, signer, sender := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 2, 100)
+
+	instrumentation := &recordingInstrumentation{}
+	client, err := server.Client(mnee.WithInstrumentation(instrumentation), mnee.WithRetryPolicy(mnee.RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+
+	t.Log("Test Case 1: Public methods get spans with their attributes...")
+	balances, err := client.GetBalances(ctx, []string{sender})
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	span := instrumentation.span("mnee.GetBalances")
+	if assertions.NotNil(span) {
+		assertions.True(span.ended)
+		assertions.NoError(span.err)
+		assertions.Equal(1, span.attrs["mnee.address_count"])
+	}
+
+	t.Log("Test Case 2: Transfers record their fee tier, txid and amount...")
+	response, err := client.NewTransferBuilder().WithSigner(signer).AddRecipients(recipients...).SubmitSync(ctx)
+	require.NoError(t, err)
+	span = instrumentation.span("mnee.TransferBuilder.SubmitSync")
+	if assertions.NotNil(span) {
+		assertions.True(span.ended)
+		assertions.Equal(2, span.attrs["mnee.recipient_count"])
+		assertions.Equal(uint64(100), span.attrs["mnee.fee"])
+		assertions.Equal(uint64(1000000), span.attrs["mnee.fee_tier_max"])
+		assertions.Equal(*response.Txid, span.attrs["mnee.cosigned_txid"])
+		assertions.NotEmpty(span.attrs["mnee.txid"])
+	}
+	if config := instrumentation.span("mnee.GetConfig"); assertions.NotNil(config) {
+		if assertions.NotNil(config.parent, "spans nest through the context") {
+			assertions.Equal("mnee.TransferBuilder.SubmitSync", config.parent.name)
+		}
+	}
+	if assertions.Len(instrumentation.transfers, 1) {
+		assertions.Equal(mnee.TransferMetric{Mode: "sync", Amount: 200, Fee: 100, Inputs: 1}, instrumentation.transfers[0])
+	}
+
+	t.Log("Test Case 3: Every request is measured, failed ones with their status...")
+	server.InjectFault(mneetest.EndpointTicket, mneetest.Fault{StatusCode: http.StatusInternalServerError})
+	_, err = client.PollTicket(ctx, "ticket", 0)
+	assertions.Error(err)
+	span = instrumentation.span("mnee.PollTicket")
+	if assertions.NotNil(span) {
+		assertions.Equal("ticket", span.attrs["mnee.ticket_id"])
+		assertions.Error(span.err)
+	}
+
+	var transfers, failed int
+	for _, metric := range instrumentation.requests {
+		if metric.Endpoint == "/v1/transfer" {
+			transfers++
+			assertions.Equal(http.StatusOK, metric.Status)
+			assertions.Positive(metric.Duration)
+		}
+		if metric.Err != nil {
+			failed++
+			assertions.Equal(http.StatusInternalServerError, metric.Status)
+		}
+	}
+	assertions.Equal(1, transfers)
+	assertions.Equal(1, failed)
+	assertions.Nil(instrumentation.span("mnee.TransferBuilder.Sign"), "submissions sign under their own span")
+
+	t.Log("Test Case 4: Building, parsing, validating and HD discovery get spans...")
+	signed, _, err := client.NewTransferBuilder().WithSigner(signer).AddRecipients(recipients...).Sign(ctx)
+	require.NoError(t, err)
+	span = instrumentation.span("mnee.TransferBuilder.Sign")
+	if assertions.NotNil(span) {
+		assertions.True(span.ended)
+		assertions.Equal(uint64(100), span.attrs["mnee.fee"])
+	}
+
+	_, err = client.ParseTransaction(ctx, signed.Hex())
+	require.NoError(t, err)
+	_, err = client.ValidateTransaction(ctx, signed.Hex(), nil)
+	require.NoError(t, err)
+	for _, name := range []string{"mnee.ParseTransaction", "mnee.ValidateTransaction"} {
+		if span = instrumentation.span(name); assertions.NotNil(span, name) {
+			assertions.True(span.ended)
+			assertions.Equal(signed.TxID().String(), span.attrs["mnee.txid"])
+		}
+	}
+
+	wallet, err := client.NewHDWalletFromSeed(make([]byte, 32), 0)
+	require.NoError(t, err)
+	_, err = wallet.Discover(ctx)
+	require.NoError(t, err)
+	_, err = wallet.NextReceiveAddress(ctx)
+	require.NoError(t, err)
+	for _, name := range []string{"mnee.HDWallet.Discover", "mnee.HDWallet.NextReceiveAddress"} {
+		if span = instrumentation.span(name); assertions.NotNil(span, name) {
+			assertions.True(span.ended)
+			assertions.NoError(span.err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// landingStatus is what the ledger says about a submitted transaction.
+type landingStatus int
+
+const (
+	// landingUnspent means every input is still unspent: the transaction did not land.
+	landingUnspent landingStatus = iota
+	// landingLanded means the cosigned transaction spent the inputs.
+	landingLanded
+	// landingConflict means another transaction spent an input, so this one can never land.
+	landingConflict
+	// landingUnknown means an input is spent but its spender is not indexed yet.
+	landingUnknown
+)
+
+// landing reports whether a submitted transaction landed. The cosigner adds its
+// signatures to every input, so the landed transaction has a different txid than
+// the submitted one: it is found in the history of the input owners as the
+// transaction spending the same outpoints into the same outputs. The landed
+// transaction is returned with landingLanded.
+func (m *MNEE) landing(ctx context.Context, submitted *transaction.Transaction) (landingStatus, *transaction.Transaction, error) {
+
+	var outpoints map[string]bool = make(map[string]bool, len(submitted.Inputs))
+	var owners []string = make([]string, 0, len(submitted.Inputs))
+	for _, input := range submitted.Inputs {
+		var outpoint string = inputOutpoint(input)
+		outpoints[outpoint] = true
+
+		txo, err := m.GetTxo(ctx, outpoint)
+		if err != nil {
+			return landingUnknown, nil, err
+		}
+
+		for _, owner := range txo.Owners {
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+
+	var unspent int
+	for txo, err := range m.AllUnspentTxos(ctx, owners, PageOptions{}) {
+		if err != nil {
+			return landingUnknown, nil, err
+		}
+
+		if txo.Outpoint != nil && outpoints[*txo.Outpoint] {
+			unspent++
+		}
+	}
+
+	if unspent == len(outpoints) {
+		return landingUnspent, nil, nil
+	}
+
+	for entry, err := range m.AllHistory(ctx, owners, PageOptions{}) {
+		if err != nil {
+			return landingUnknown, nil, err
+		}
+
+		if entry.Rawtx == nil {
+			continue
+		}
+
+		spender, err := decodeHistoryTx(*entry.Rawtx)
+		if err != nil {
+			continue
+		}
+
+		if !slices.ContainsFunc(spender.Inputs, func(input *transaction.TransactionInput) bool {
+			return outpoints[inputOutpoint(input)]
+		}) {
+			continue
+		}
+
+		if sameTransfer(spender, submitted) {
+			return landingLanded, spender, nil
+		}
+
+		return landingConflict, nil, nil
+	}
+
+	return landingUnknown, nil, nil
+}
+
+// inputOutpoint formats the "txid_vout" outpoint spent by an input.
+func inputOutpoint(input *transaction.TransactionInput) string {
+	return fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)
+}
+
+// decodeHistoryTx decodes the base64 raw transaction of a history entry.
+func decodeHistoryTx(rawTx string) (*transaction.Transaction, error) {
+
+	txBytes, err := base64.StdEncoding.DecodeString(rawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.NewTransactionFromBytes(txBytes)
+}
+
+// sameTransfer reports whether `landed` is the cosigned form of `submitted`:
+// it spends every submitted outpoint into exactly the submitted outputs. The
+// cosigner adds its own funding inputs, which the AnyOneCanPay signatures allow,
+// so `landed` may have more inputs, in any order; the outputs are covered by the
+// signatures and must match one for one.
+func sameTransfer(landed *transaction.Transaction, submitted *transaction.Transaction) bool {
+
+	if len(landed.Outputs) != len(submitted.Outputs) {
+		return false
+	}
+
+	for _, input := range submitted.Inputs {
+		var outpoint string = inputOutpoint(input)
+		if !slices.ContainsFunc(landed.Inputs, func(landedInput *transaction.TransactionInput) bool {
+			return inputOutpoint(landedInput) == outpoint
+		}) {
+			return false
+		}
+	}
+
+	for i := range landed.Outputs {
+		if landed.Outputs[i].Satoshis != submitted.Outputs[i].Satoshis ||
+			!bytes.Equal(landed.Outputs[i].LockingScript.Bytes(), submitted.Outputs[i].LockingScript.Bytes()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// landedOrRetry decides, before a submission is retried, whether it already
+// landed. A landed transaction stops the retries with ErrTransferAlreadySubmitted,
+// a conflicting one with ErrInputsSpent. A transaction whose inputs are unknown
+// to the API is retried; other lookup errors stop the retries.
+func (m *MNEE) landedOrRetry(ctx context.Context, submitted *transaction.Transaction) (*transaction.Transaction, error) {
+
+	status, landed, err := m.landing(ctx, submitted)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case landingLanded:
+		return landed, fmt.Errorf("%w: %s", ErrTransferAlreadySubmitted, landed.TxID().String())
+	case landingConflict:
+		return nil, ErrInputsSpent
+	default:
+		return nil, nil
+	}
+}
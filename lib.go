@@ -1,8 +1,10 @@
 package mnee
 
 import (
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -11,24 +13,44 @@ const (
 	EnvMain string = "MAIN"
 	// EnvSandbox specifies the MNEE sandbox environment URL.
 	EnvSandbox string = "SANDBOX"
+	// EnvCustom specifies a caller-provided environment. It requires WithBaseURL.
+	EnvCustom string = "CUSTOM"
+)
+
+const (
+	mainURL    string = "https://proxy-api.mnee.net"
+	sandboxURL string = "https://sandbox-proxy-api.mnee.net"
+
+	defaultUserAgent string = "go-mnee-1sat-sdk"
 )
 
 // MNEE provides the client for interacting with the MNEE API.
 // It holds the API configuration, HTTP client, and caches the system config.
 type MNEE struct {
-	mneeURL      string
-	mneeToken    string
-	mutex        *sync.Mutex
-	httpClient   *http.Client
-	config       *SystemConfig
-	refreshTimer <-chan time.Time
+	mneeURL       string
+	mneeToken     atomic.Pointer[string]
+	authenticator Authenticator
+	userAgent     string
+	timeout       time.Duration
+	retryPolicy   RetryPolicy
+	mutex         *sync.Mutex
+	httpClient    *http.Client
+	config        *SystemConfig
+	refreshTimer  <-chan time.Time
+	reservations  *utxoReservations
+	// transferStore records submissions for Resume; nil disables it.
+	transferStore   TransferStore
+	logger          *slog.Logger
+	instrumentation Instrumentation
 }
 
 // NewMneeInstance creates a new MNEE client instance.
 //
-// It requires an environment (`EnvMain` or `EnvSandbox`) and an authToken.
+// It requires an environment (`EnvMain`, `EnvSandbox` or `EnvCustom`) and an authToken.
+// Optional behaviour such as the base URL, HTTP client or timeouts can be
+// customised with Option values. `EnvCustom` must be combined with WithBaseURL.
 // The client automatically fetches and caches the MNEE system configuration.
-func NewMneeInstance(environment string, authToken string) (*MNEE, error) {
+func NewMneeInstance(environment string, authToken string, options ...Option) (*MNEE, error) {
 
 	var mnee MNEE
 
@@ -36,20 +58,27 @@ func NewMneeInstance(environment string, authToken string) (*MNEE, error) {
 
 	case EnvMain:
 		{
-			mnee.mneeURL = "https://proxy-api.mnee.net"
-			mnee.mneeToken = authToken
+			mnee.mneeURL = mainURL
 		}
 
 	case EnvSandbox:
 		{
-			mnee.mneeURL = "https://sandbox-proxy-api.mnee.net"
-			mnee.mneeToken = authToken
+			mnee.mneeURL = sandboxURL
 		}
 
+	case EnvCustom:
+		// The base URL is set by WithBaseURL.
+
 	default:
 		return nil, ErrInvalidEnvironment
 	}
 
+	mnee.SetAuthToken(authToken)
+	mnee.authenticator = HeaderAuthenticator{}
+	mnee.userAgent = defaultUserAgent
+	mnee.retryPolicy = DefaultRetryPolicy()
+	mnee.logger = slog.New(slog.DiscardHandler)
+	mnee.instrumentation = NoopInstrumentation{}
 	mnee.mutex = new(sync.Mutex)
 	mnee.httpClient = &http.Client{
 		Transport: &http.Transport{
@@ -58,6 +87,28 @@ func NewMneeInstance(environment string, authToken string) (*MNEE, error) {
 		},
 		Timeout: 0,
 	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+
+		err := option(&mnee)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mnee.mneeURL == "" {
+		return nil, ErrMissingBaseURL
+	}
+
+	if mnee.timeout > 0 {
+		var httpClient http.Client = *mnee.httpClient
+		httpClient.Timeout = mnee.timeout
+		mnee.httpClient = &httpClient
+	}
+
 	mnee.refreshTimer = time.Tick(time.Hour)
 
 	return &mnee, nil
@@ -0,0 +1,63 @@
+package mnee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMneeInstance_Options(t *testing.T) {
+	assertions := assert.New(t)
+
+	var receivedUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decimals":5}`))
+	}))
+	defer server.Close()
+
+	t.Log("Test Case 1: Custom environment without a base URL is rejected...")
+	_, err := NewMneeInstance(EnvCustom, "token")
+	assertions.ErrorIs(err, ErrMissingBaseURL)
+
+	t.Log("Test Case 2: Invalid base URL and nil HTTP client are rejected...")
+	_, err = NewMneeInstance(EnvCustom, "token", WithBaseURL("not-a-url"))
+	assertions.ErrorIs(err, ErrInvalidBaseURL)
+	_, err = NewMneeInstance(EnvMain, "token", WithHTTPClient(nil))
+	assertions.ErrorIs(err, ErrNilHTTPClient)
+
+	t.Log("Test Case 3: The original two-argument call still works...")
+	m, err := NewMneeInstance(EnvSandbox, "token")
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(sandboxURL, m.mneeURL)
+	assertions.Equal(defaultUserAgent, m.userAgent)
+
+	t.Log("Test Case 4: Options are applied to the client...")
+	httpClient := &http.Client{}
+	m, err = NewMneeInstance(EnvCustom, "token",
+		WithBaseURL(server.URL+"/"),
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithUserAgent("payout-service/1.0"),
+	)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(server.URL, m.mneeURL, "Trailing slash should be trimmed")
+	assertions.Equal(5*time.Second, m.httpClient.Timeout)
+	assertions.Equal(time.Duration(0), httpClient.Timeout, "Caller's HTTP client must not be modified")
+
+	config, err := m.GetConfig(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint8(5), config.Decimals)
+	assertions.Equal("payout-service/1.0", receivedUserAgent)
+}
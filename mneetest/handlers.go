@@ -0,0 +1,368 @@
+package mneetest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// recordNotFound is the message the cosigner answers unknown records with.
+const recordNotFound string = "record not found"
+
+// endpointOf maps a request path to its endpoint name.
+func endpointOf(path string) string {
+
+	switch {
+	case strings.HasPrefix(path, EndpointTx+"/"):
+		return EndpointTx
+	case strings.HasPrefix(path, EndpointTxos+"/"):
+		return EndpointTxos
+	default:
+		return path
+	}
+}
+
+// serveHTTP counts the call, applies injected latency and faults, checks the
+// auth token and dispatches to the endpoint handler.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	var endpoint string = endpointOf(r.URL.Path)
+
+	s.mutex.Lock()
+	s.calls[endpoint]++
+	var delay time.Duration = s.latency[endpoint]
+	var fault *Fault
+	if faults := s.faults[endpoint]; len(faults) > 0 {
+		fault = &faults[0]
+		s.faults[endpoint] = faults[1:]
+	}
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil {
+		for key, value := range fault.Header {
+			w.Header().Set(key, value)
+		}
+
+		var body string = fault.Body
+		if body == "" {
+			encoded, _ := json.Marshal(map[string]string{"message": http.StatusText(fault.StatusCode)})
+			body = string(encoded)
+		}
+
+		w.WriteHeader(fault.StatusCode)
+		_, _ = w.Write([]byte(body))
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.Token && r.URL.Query().Get("auth_token") != s.Token {
+		writeMessage(w, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch endpoint {
+	case EndpointConfig:
+		writeJSON(w, s.Config)
+	case EndpointUtxos:
+		s.handleUtxos(w, r, false)
+	case EndpointPagedUtxos:
+		s.handleUtxos(w, r, true)
+	case EndpointBalance:
+		s.handleBalance(w, r)
+	case EndpointSync:
+		s.handleSync(w, r)
+	case EndpointTx:
+		s.handleTx(w, r)
+	case EndpointTxos:
+		s.handleTxos(w, r)
+	case EndpointTransfer:
+		s.handleTransfer(w, r)
+	case EndpointTransferAsync:
+		s.handleTransferAsync(w, r)
+	case EndpointTicket:
+		s.handleTicket(w, r)
+	default:
+		writeMessage(w, http.StatusNotFound, recordNotFound)
+	}
+}
+
+// handleUtxos serves /v1/utxos and the paginated /v2/utxos (1-based pages).
+func (s *Server) handleUtxos(w http.ResponseWriter, r *http.Request, paged bool) {
+
+	var addresses []string
+	if json.NewDecoder(r.Body).Decode(&addresses) != nil {
+		writeMessage(w, http.StatusBadRequest, "invalid addresses")
+		return
+	}
+
+	var txos []mnee.MneeTxo = make([]mnee.MneeTxo, 0)
+	for _, outpoint := range s.order {
+		txo := s.txos[outpoint]
+		if !s.spent[outpoint] && slices.Contains(addresses, txo.Owners[0]) {
+			txos = append(txos, *txo)
+		}
+	}
+
+	if paged {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		start := min(max(page-1, 0)*max(size, 0), len(txos))
+		txos = txos[start:min(start+max(size, 0), len(txos))]
+	}
+
+	writeJSON(w, txos)
+}
+
+// handleBalance serves /v2/balance.
+func (s *Server) handleBalance(w http.ResponseWriter, r *http.Request) {
+
+	var addresses []string
+	if json.NewDecoder(r.Body).Decode(&addresses) != nil {
+		writeMessage(w, http.StatusBadRequest, "invalid addresses")
+		return
+	}
+
+	var divisor float64 = 1
+	for range s.Config.Decimals {
+		divisor *= 10
+	}
+
+	var balances []mnee.BalanceDataDTO = make([]mnee.BalanceDataDTO, 0, len(addresses))
+	for _, address := range addresses {
+		var amount uint64
+		for _, outpoint := range s.order {
+			if !s.spent[outpoint] && s.txos[outpoint].Owners[0] == address {
+				amount += s.txos[outpoint].Data.Bsv21.Amt
+			}
+		}
+
+		balances = append(balances, mnee.BalanceDataDTO{
+			Address:  &address,
+			Amt:      float64(amount),
+			Precised: float64(amount) / divisor,
+		})
+	}
+
+	writeJSON(w, balances)
+}
+
+// handleSync serves /v1/sync, the history of transactions sent or received by the addresses.
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+
+	var addresses []string
+	if json.NewDecoder(r.Body).Decode(&addresses) != nil {
+		writeMessage(w, http.StatusBadRequest, "invalid addresses")
+		return
+	}
+
+	var history []mnee.TransactionHistoryDTO = make([]mnee.TransactionHistoryDTO, 0)
+	for _, entry := range s.history {
+		for _, address := range addresses {
+			if slices.Contains(entry.Senders, address) || slices.Contains(entry.Receivers, address) {
+				history = append(history, entry)
+				break
+			}
+		}
+	}
+
+	from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = len(history)
+	}
+
+	start := min(max(from, 0), len(history))
+	writeJSON(w, history[start:min(start+limit, len(history))])
+}
+
+// handleTx serves /v1/tx/{txid} with the base64 raw transaction.
+func (s *Server) handleTx(w http.ResponseWriter, r *http.Request) {
+
+	rawTx, ok := s.txs[strings.TrimPrefix(r.URL.Path, EndpointTx+"/")]
+	if !ok {
+		writeMessage(w, http.StatusNotFound, recordNotFound)
+		return
+	}
+
+	writeJSON(w, map[string]string{"rawtx": base64.StdEncoding.EncodeToString(rawTx)})
+}
+
+// handleTxos serves /v2/txos/{outpoint}, spent or not.
+func (s *Server) handleTxos(w http.ResponseWriter, r *http.Request) {
+
+	txo, ok := s.txos[strings.TrimPrefix(r.URL.Path, EndpointTxos+"/")]
+	if !ok {
+		writeMessage(w, http.StatusNotFound, recordNotFound)
+		return
+	}
+
+	writeJSON(w, txo)
+}
+
+// handleTransfer serves /v1/transfer, cosigning synchronously.
+func (s *Server) handleTransfer(w http.ResponseWriter, r *http.Request) {
+
+	var request mnee.TransferRequestDTO
+	txBytes, ok := decodeTransferRequest(w, r, &request)
+	if !ok {
+		return
+	}
+
+	cosigned, err := s.cosign(txBytes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, map[string]string{"rawtx": base64.StdEncoding.EncodeToString(cosigned.Bytes())})
+}
+
+// handleTransferAsync serves /v2/transfer. The transfer is cosigned right away
+// and its ticket starts BROADCASTING (or FAILED if it is rejected), moving to
+// SUCCESS once it has been polled.
+func (s *Server) handleTransferAsync(w http.ResponseWriter, r *http.Request) {
+
+	var request mnee.TransferRequestDTO
+	txBytes, ok := decodeTransferRequest(w, r, &request)
+	if !ok {
+		return
+	}
+
+	var ticketID string = s.newTxid("ticket")
+	var now time.Time = time.Now()
+	var action string = "transfer"
+	var ticket mnee.Ticket = mnee.Ticket{
+		ID:              &ticketID,
+		ActionRequested: &action,
+		CallbackURL:     request.CallbackURL,
+		CallbackSecret:  request.CallbackSecret,
+		Status:          mnee.BROADCASTING,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
+		Errors:          []string{},
+	}
+
+	cosigned, err := s.cosign(txBytes)
+	if errors.Is(err, errRejected) {
+		ticket.Status = mnee.FAILED
+		ticket.Errors = []string{err.Error()}
+	} else if err != nil {
+		writeError(w, err)
+		return
+	} else {
+		var txid string = cosigned.TxID().String()
+		var txHex string = cosigned.Hex()
+		ticket.TxID = &txid
+		ticket.TxHex = &txHex
+	}
+
+	s.tickets[ticketID] = &ticket
+	s.notify(ticket)
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(ticketID))
+}
+
+// handleTicket serves /v2/ticket?ticketID=.
+func (s *Server) handleTicket(w http.ResponseWriter, r *http.Request) {
+
+	ticket, ok := s.tickets[r.URL.Query().Get("ticketID")]
+	if !ok {
+		writeMessage(w, http.StatusNotFound, recordNotFound)
+		return
+	}
+
+	var response mnee.Ticket = *ticket
+	response.CallbackSecret = nil
+	writeJSON(w, response)
+
+	if ticket.Status == mnee.BROADCASTING {
+		var now time.Time = time.Now()
+		ticket.Status = mnee.SUCCESS
+		ticket.UpdatedAt = &now
+		s.notify(*ticket)
+	}
+}
+
+// notify delivers a ticket to its callback URL in the background, as the cosigner does.
+func (s *Server) notify(ticket mnee.Ticket) {
+
+	if ticket.CallbackURL == nil || *ticket.CallbackURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(&ticket)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		response, err := http.Post(*ticket.CallbackURL, "application/json", bytes.NewReader(payload))
+		if err == nil {
+			response.Body.Close()
+		}
+	}()
+}
+
+// decodeTransferRequest decodes the base64 rawtx of a transfer request,
+// answering 400 itself when the request is malformed.
+func decodeTransferRequest(w http.ResponseWriter, r *http.Request, request *mnee.TransferRequestDTO) ([]byte, bool) {
+
+	if json.NewDecoder(r.Body).Decode(request) != nil || request.RawTx == "" {
+		writeMessage(w, http.StatusBadRequest, "invalid transfer request")
+		return nil, false
+	}
+
+	txBytes, err := base64.StdEncoding.DecodeString(request.RawTx)
+	if err != nil {
+		writeMessage(w, http.StatusBadRequest, "invalid rawtx encoding")
+		return nil, false
+	}
+
+	return txBytes, true
+}
+
+// writeError answers rejected transfers with 400 and anything else with 500.
+func writeError(w http.ResponseWriter, err error) {
+
+	if errors.Is(err, errRejected) {
+		writeMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeMessage(w, http.StatusInternalServerError, err.Error())
+}
+
+// writeMessage writes a {"message": ...} error body like the cosigner.
+func writeMessage(w http.ResponseWriter, status int, message string) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// writeJSON writes a 200 JSON response.
+func writeJSON(w http.ResponseWriter, value any) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,358 @@
+package mneetest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// userSighashFlags is the flag the SDK signs owner inputs with.
+const userSighashFlags sighash.Flag = sighash.ForkID | sighash.All | sighash.AnyOneCanPay
+
+// transferOutput is a decoded MNEE output.
+type transferOutput struct {
+	address string
+	amount  uint64
+}
+
+// errRejected marks transfers the cosigner refuses; they are answered with 400.
+var errRejected = errors.New("transfer rejected")
+
+// addTransferOutput appends a cosigner-locked MNEE transfer inscription.
+func (s *Server) addTransferOutput(mneeTransaction *transaction.Transaction, address string, amount uint64) error {
+
+	scriptAddress, err := script.NewAddressFromString(address)
+	if err != nil {
+		return err
+	}
+
+	var lockingScript script.Script
+	_ = lockingScript.AppendOpcodes(script.OpDUP, script.OpHASH160)
+	_ = lockingScript.AppendPushData(scriptAddress.PublicKeyHash)
+	_ = lockingScript.AppendOpcodes(script.OpEQUALVERIFY, script.OpCHECKSIGVERIFY)
+	_ = lockingScript.AppendPushData(s.Approver.PubKey().Compressed())
+	_ = lockingScript.AppendOpcodes(script.OpCHECKSIG)
+
+	inscription, err := json.Marshal(map[string]string{
+		"p":   "bsv-20",
+		"op":  "transfer",
+		"id":  *s.Config.TokenId,
+		"amt": strconv.FormatUint(amount, 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	return mneeTransaction.Inscribe(&script.InscriptionArgs{
+		ContentType:   "application/bsv-20",
+		Data:          inscription,
+		LockingScript: &lockingScript,
+	})
+}
+
+// decodeOutput decodes a MNEE transfer output locked to the server's approver.
+func (s *Server) decodeOutput(lockingScript *script.Script) (transferOutput, error) {
+
+	chunks, err := lockingScript.Chunks()
+	if err != nil {
+		return transferOutput{}, err
+	}
+
+	if len(chunks) != 15 || chunks[9].Op != script.OpHASH160 || chunks[12].Op != script.OpCHECKSIGVERIFY ||
+		!bytes.Equal(chunks[13].Data, s.Approver.PubKey().Compressed()) {
+		return transferOutput{}, fmt.Errorf("%w: output is not locked to the approver", errRejected)
+	}
+
+	var inscription struct {
+		Protocol  string `json:"p"`
+		Operation string `json:"op"`
+		TokenID   string `json:"id"`
+		Amount    string `json:"amt"`
+	}
+
+	err = json.Unmarshal(chunks[6].Data, &inscription)
+	if err != nil || inscription.Protocol != "bsv-20" || inscription.TokenID != *s.Config.TokenId {
+		return transferOutput{}, fmt.Errorf("%w: output is not a MNEE inscription", errRejected)
+	}
+
+	amount, err := strconv.ParseUint(inscription.Amount, 10, 64)
+	if err != nil || amount == 0 {
+		return transferOutput{}, fmt.Errorf("%w: invalid amount %q", errRejected, inscription.Amount)
+	}
+
+	address, err := script.NewAddressFromPublicKeyHash(chunks[10].Data, true)
+	if err != nil {
+		return transferOutput{}, err
+	}
+
+	return transferOutput{address: address.AddressString, amount: amount}, nil
+}
+
+// cosign verifies a transfer signed by its owners, checks its inputs against
+// the ledger and its amounts and fee against the system config, adds a funding
+// input and the approver signatures, and records it. The caller must hold the mutex.
+func (s *Server) cosign(txBytes []byte) (*transaction.Transaction, error) {
+
+	mneeTransaction, err := transaction.NewTransactionFromBytes(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errRejected, err)
+	}
+
+	var senders []string = make([]string, 0)
+	var totalInput uint64
+	for vin, input := range mneeTransaction.Inputs {
+		outpoint := fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)
+		txo, ok := s.txos[outpoint]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown input %s", errRejected, outpoint)
+		}
+
+		if s.spent[outpoint] {
+			return nil, fmt.Errorf("%w: input %s already spent", errRejected, outpoint)
+		}
+
+		lockingScript, err := decodeTxoScript(txo)
+		if err != nil {
+			return nil, err
+		}
+
+		input.SetSourceTxOutput(&transaction.TransactionOutput{Satoshis: uint64(txo.Satoshis), LockingScript: lockingScript})
+
+		err = verifyOwnerSignature(mneeTransaction, vin, txo.Owners[0])
+		if err != nil {
+			return nil, err
+		}
+
+		totalInput += txo.Data.Bsv21.Amt
+		senders = append(senders, txo.Owners[0])
+	}
+
+	var totalOutput uint64
+	var feeBase uint64
+	var feePaid uint64
+	var feeOutput bool
+	for _, output := range mneeTransaction.Outputs {
+		decoded, err := s.decodeOutput(output.LockingScript)
+		if err != nil {
+			return nil, err
+		}
+
+		totalOutput += decoded.amount
+		switch {
+		case decoded.address == *s.Config.FeeAddress:
+			feeOutput = true
+			feePaid += decoded.amount
+		case !slices.Contains(senders, decoded.address):
+			feeBase += decoded.amount
+		}
+	}
+
+	if totalInput != totalOutput {
+		return nil, fmt.Errorf("%w: inputs total %d but outputs total %d", errRejected, totalInput, totalOutput)
+	}
+
+	err = s.checkFee(feeBase, feePaid, feeOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cosigner pays the mining fee from an input of its own, which the
+	// owners' AnyOneCanPay signatures allow and which changes the txid.
+	var mneeInputs int = len(mneeTransaction.Inputs)
+	err = s.addFundingInput(mneeTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	for vin, input := range mneeTransaction.Inputs {
+		signatureHash, err := mneeTransaction.CalcInputSignatureHash(uint32(vin), sighash.ForkID|sighash.All)
+		if err != nil {
+			return nil, err
+		}
+
+		signature, err := s.Approver.Sign(signatureHash)
+		if err != nil {
+			return nil, err
+		}
+
+		var unlockingScript script.Script
+		_ = unlockingScript.AppendPushData(append(signature.Serialize(), uint8(sighash.ForkID|sighash.All)))
+		if vin < mneeInputs {
+			unlockingScript = append(unlockingScript, *input.UnlockingScript...)
+		} else {
+			_ = unlockingScript.AppendPushData(s.Approver.PubKey().Compressed())
+		}
+		input.UnlockingScript = &unlockingScript
+	}
+
+	s.recordTransaction(mneeTransaction, senders, 0)
+	return mneeTransaction, nil
+}
+
+// checkFee checks the fee paid to SystemConfig.FeeAddress against the fee tier
+// of `feeBase`, the amount sent outside the input addresses and the fee address.
+func (s *Server) checkFee(feeBase uint64, feePaid uint64, feeOutput bool) error {
+
+	var tier *mnee.Fee
+	for i := range s.Config.Fees {
+		if feeBase >= s.Config.Fees[i].MinAmt && feeBase <= s.Config.Fees[i].MaxAmt {
+			tier = &s.Config.Fees[i]
+			break
+		}
+	}
+
+	switch {
+	case tier == nil:
+		return fmt.Errorf("%w: no fee tier for transfer amount %d", errRejected, feeBase)
+	case tier.Fee > 0 && !feeOutput:
+		return fmt.Errorf("%w: fee of %d must be paid to %s", errRejected, tier.Fee, *s.Config.FeeAddress)
+	case feePaid != tier.Fee:
+		return fmt.Errorf("%w: fee paid is %d but the tier for %d requires %d", errRejected, feePaid, feeBase, tier.Fee)
+	}
+
+	return nil
+}
+
+// addFundingInput appends an input spending a satoshi output of the approver,
+// as the cosigner does to pay the mining fee. The input is signed with the
+// MNEE inputs.
+func (s *Server) addFundingInput(mneeTransaction *transaction.Transaction) error {
+
+	approverAddress, err := script.NewAddressFromPublicKey(s.Approver.PubKey(), true)
+	if err != nil {
+		return err
+	}
+
+	fundingScript, err := p2pkh.Lock(approverAddress)
+	if err != nil {
+		return err
+	}
+
+	return mneeTransaction.AddInputFrom(s.newTxid("funding"), 0, fundingScript.String(), 1000, nil)
+}
+
+// recordTransaction spends the inputs of a cosigned transaction, adds its
+// outputs to the ledger and its history entry. The caller must hold the mutex.
+func (s *Server) recordTransaction(mneeTransaction *transaction.Transaction, senders []string, height uint64) {
+
+	var txid string = mneeTransaction.TxID().String()
+	var rawTx []byte = mneeTransaction.Bytes()
+	s.txs[txid] = rawTx
+
+	for _, input := range mneeTransaction.Inputs {
+		s.spent[fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)] = true
+	}
+
+	var receivers []string = make([]string, 0, len(mneeTransaction.Outputs))
+	var outs []uint64 = make([]uint64, 0, len(mneeTransaction.Outputs))
+	for vout, output := range mneeTransaction.Outputs {
+		decoded, err := s.decodeOutput(output.LockingScript)
+		if err != nil {
+			continue
+		}
+
+		var outpoint string = fmt.Sprintf("%s_%d", txid, vout)
+		var encodedScript string = encodeScript(output.LockingScript)
+		var op string = "transfer"
+		var owner string = decoded.address
+
+		s.txos[outpoint] = &mnee.MneeTxo{
+			Satoshis: uint16(output.Satoshis),
+			Height:   height,
+			Score:    uint64(len(s.order)),
+			Vout:     uint64(vout),
+			Outpoint: &outpoint,
+			Script:   &encodedScript,
+			Txid:     &txid,
+			Owners:   []string{owner},
+			Senders:  senders,
+			Data: &mnee.Data{
+				Bsv21: &mnee.BsvData{
+					Amt:      decoded.amount,
+					Decimals: s.Config.Decimals,
+					Id:       s.Config.TokenId,
+					Op:       &op,
+				},
+				Cosign: &mnee.CosignData{Address: &owner, Cosigner: s.Config.Approver},
+			},
+		}
+		s.order = append(s.order, outpoint)
+
+		receivers = append(receivers, owner)
+		outs = append(outs, uint64(vout))
+	}
+
+	var encodedTx string = base64.StdEncoding.EncodeToString(rawTx)
+	s.history = append(s.history, mnee.TransactionHistoryDTO{
+		Height:    height,
+		Score:     uint64(len(s.history)),
+		Txid:      &txid,
+		Rawtx:     &encodedTx,
+		Outs:      outs,
+		Senders:   senders,
+		Receivers: receivers,
+	})
+}
+
+// decodeTxoScript decodes the base64 locking script of a ledger UTXO.
+func decodeTxoScript(txo *mnee.MneeTxo) (*script.Script, error) {
+
+	scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	return script.NewFromBytes(scriptBytes), nil
+}
+
+// verifyOwnerSignature checks that an input is unlocked by its owner with the SDK's sighash flags.
+func verifyOwnerSignature(mneeTransaction *transaction.Transaction, vin int, owner string) error {
+
+	input := mneeTransaction.Inputs[vin]
+	if input.UnlockingScript == nil {
+		return fmt.Errorf("%w: input %d is not signed", errRejected, vin)
+	}
+
+	chunks, err := input.UnlockingScript.Chunks()
+	if err != nil || len(chunks) != 2 || len(chunks[0].Data) < 2 {
+		return fmt.Errorf("%w: input %d has a malformed unlocking script", errRejected, vin)
+	}
+
+	signatureBytes := chunks[0].Data
+	if sighash.Flag(signatureBytes[len(signatureBytes)-1]) != userSighashFlags {
+		return fmt.Errorf("%w: input %d must be signed with ForkID|All|AnyOneCanPay", errRejected, vin)
+	}
+
+	signature, err := primitives.ParseDERSignature(signatureBytes[:len(signatureBytes)-1])
+	if err != nil {
+		return fmt.Errorf("%w: input %d: %v", errRejected, vin, err)
+	}
+
+	publicKey, err := primitives.PublicKeyFromBytes(chunks[1].Data)
+	if err != nil {
+		return fmt.Errorf("%w: input %d: %v", errRejected, vin, err)
+	}
+
+	address, err := script.NewAddressFromPublicKey(publicKey, true)
+	if err != nil || address.AddressString != owner {
+		return fmt.Errorf("%w: input %d is not signed by %s", errRejected, vin, owner)
+	}
+
+	signatureHash, err := mneeTransaction.CalcInputSignatureHash(uint32(vin), userSighashFlags)
+	if err != nil || !signature.Verify(signatureHash, publicKey) {
+		return fmt.Errorf("%w: input %d has an invalid signature", errRejected, vin)
+	}
+
+	return nil
+}
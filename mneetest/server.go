@@ -0,0 +1,250 @@
+// Package mneetest provides an in-process fake of the MNEE cosigner API for
+// hermetic tests of code built on the SDK.
+//
+// The fake keeps an in-memory UTXO ledger and checks submitted transfers the
+// way the cosigner does: known unspent inputs signed by their owners, inputs
+// equal to outputs, and the fee tier of the amount sent outside the input
+// addresses paid to the fee address. Like the cosigner it adds a funding input
+// of its own before signing, so the landed txid differs from the submitted one.
+// It serves every endpoint used by the mnee package. Faults and latency can be
+// injected per endpoint.
+//
+//	server := mneetest.NewServer()
+//	defer server.Close()
+//
+//	server.Fund(address, 10000)
+//	client, err := server.Client()
+package mneetest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// DefaultToken is the API token accepted by a new Server.
+const DefaultToken string = "mneetest-token"
+
+// Endpoint names accepted by InjectFault, SetLatency and Calls. They match
+// the endpoint reported in mnee.APIError.
+const (
+	EndpointConfig        string = "/v1/config"
+	EndpointUtxos         string = "/v1/utxos"
+	EndpointPagedUtxos    string = "/v2/utxos"
+	EndpointBalance       string = "/v2/balance"
+	EndpointSync          string = "/v1/sync"
+	EndpointTx            string = "/v1/tx"
+	EndpointTxos          string = "/v2/txos"
+	EndpointTransfer      string = "/v1/transfer"
+	EndpointTransferAsync string = "/v2/transfer"
+	EndpointTicket        string = "/v2/ticket"
+)
+
+// Fault is an error response returned instead of handling a request.
+type Fault struct {
+	StatusCode int
+	// Body is sent verbatim. Leave empty for a {"message": <status text>} body.
+	Body string
+	// Header is added to the response, e.g. {"Retry-After": "1"}.
+	Header map[string]string
+}
+
+// Server is a fake MNEE cosigner backed by httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API token every request must carry, as a bearer token or
+	// as the auth_token query parameter.
+	Token string
+	// Approver is the cosigner key whose public key is SystemConfig.Approver.
+	Approver *primitives.PrivateKey
+	// Config is served by /v1/config. Edit it before the first client call.
+	Config mnee.SystemConfig
+
+	mutex    sync.Mutex
+	txos     map[string]*mnee.MneeTxo
+	order    []string
+	spent    map[string]bool
+	txs      map[string][]byte
+	history  []mnee.TransactionHistoryDTO
+	tickets  map[string]*mnee.Ticket
+	faults   map[string][]Fault
+	latency  map[string]time.Duration
+	calls    map[string]int
+	sequence uint64
+}
+
+// NewServer starts a fake cosigner with a fresh approver key and a
+// SystemConfig with 5 decimals and two fee tiers: 100 up to 1,000,000 atomic
+// units and 1,000 above. The caller must Close it.
+func NewServer() *Server {
+
+	approver, err := primitives.NewPrivateKey()
+	if err != nil {
+		panic(fmt.Sprintf("mneetest: generating approver key: %v", err))
+	}
+
+	var server Server = Server{
+		Token:    DefaultToken,
+		Approver: approver,
+		txos:     make(map[string]*mnee.MneeTxo),
+		spent:    make(map[string]bool),
+		txs:      make(map[string][]byte),
+		tickets:  make(map[string]*mnee.Ticket),
+		faults:   make(map[string][]Fault),
+		latency:  make(map[string]time.Duration),
+		calls:    make(map[string]int),
+	}
+
+	var approverHex string = hex.EncodeToString(approver.PubKey().Compressed())
+	var tokenID string = server.newTxid("token") + "_0"
+	server.Config = mnee.SystemConfig{
+		Decimals:    5,
+		Approver:    &approverHex,
+		FeeAddress:  server.newAddress("fee"),
+		BurnAddress: server.newAddress("burn"),
+		MintAddress: server.newAddress("mint"),
+		TokenId:     &tokenID,
+		Fees: []mnee.Fee{
+			{MinAmt: 0, MaxAmt: 1000000, Fee: 100},
+			{MinAmt: 1000001, MaxAmt: math.MaxUint64, Fee: 1000},
+		},
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return &server
+}
+
+// Client returns a mnee client for the fake, using EnvCustom and the server's token.
+func (s *Server) Client(options ...mnee.Option) (*mnee.MNEE, error) {
+	return mnee.NewMneeInstance(mnee.EnvCustom, s.Token, append([]mnee.Option{mnee.WithBaseURL(s.URL)}, options...)...)
+}
+
+// Fund mints a confirmed MNEE UTXO of `amount` atomic units to `address` and returns it.
+func (s *Server) Fund(address string, amount uint64) (mnee.MneeTxo, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var mintTransaction *transaction.Transaction = transaction.NewTransaction()
+	err := s.addTransferOutput(mintTransaction, address, amount)
+	if err != nil {
+		return mnee.MneeTxo{}, err
+	}
+
+	// Funding transactions have no inputs; a nonce in the lock time keeps their txids apart.
+	s.sequence++
+	mintTransaction.LockTime = uint32(s.sequence)
+
+	s.recordTransaction(mintTransaction, nil, 1)
+	return *s.txos[fmt.Sprintf("%s_0", mintTransaction.TxID().String())], nil
+}
+
+// Unspent returns the unspent UTXOs of the ledger in creation order.
+func (s *Server) Unspent() []mnee.MneeTxo {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var unspent []mnee.MneeTxo = make([]mnee.MneeTxo, 0)
+	for _, outpoint := range s.order {
+		if !s.spent[outpoint] {
+			unspent = append(unspent, *s.txos[outpoint])
+		}
+	}
+
+	return unspent
+}
+
+// Balance returns the unspent MNEE of `address` in atomic units.
+func (s *Server) Balance(address string) uint64 {
+
+	var balance uint64
+	for _, txo := range s.Unspent() {
+		if txo.Owners[0] == address {
+			balance += txo.Data.Bsv21.Amt
+		}
+	}
+
+	return balance
+}
+
+// InjectFault queues faults for `endpoint`. Each request to the endpoint
+// consumes the next queued fault until none are left.
+func (s *Server) InjectFault(endpoint string, faults ...Fault) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults[endpoint] = append(s.faults[endpoint], faults...)
+}
+
+// SetLatency delays every response of `endpoint` by `delay`.
+func (s *Server) SetLatency(endpoint string, delay time.Duration) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latency[endpoint] = delay
+}
+
+// Calls returns how many requests `endpoint` received, including faulted ones.
+func (s *Server) Calls(endpoint string) int {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.calls[endpoint]
+}
+
+// SetTicketStatus overrides the status and errors of an asynchronous transfer ticket.
+func (s *Server) SetTicketStatus(ticketID string, status mnee.TicketStatus, errors ...string) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if ticket, ok := s.tickets[ticketID]; ok {
+		ticket.Status = status
+		ticket.Errors = errors
+	}
+}
+
+// newTxid derives a deterministic, unique txid-like hex string.
+func (s *Server) newTxid(label string) string {
+
+	s.sequence++
+	hash := sha256.Sum256(fmt.Appendf(nil, "mneetest-%s-%d", label, s.sequence))
+	return hex.EncodeToString(hash[:])
+}
+
+// newAddress derives a fresh address for the system config.
+func (s *Server) newAddress(label string) *string {
+
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		panic(fmt.Sprintf("mneetest: generating %s key: %v", label, err))
+	}
+
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		panic(fmt.Sprintf("mneetest: deriving %s address: %v", label, err))
+	}
+
+	return &address.AddressString
+}
+
+// encodeScript encodes a locking script the way the API serves it.
+func encodeScript(lockingScript *script.Script) string {
+	return base64.StdEncoding.EncodeToString(lockingScript.Bytes())
+}
@@ -0,0 +1,278 @@
+package mneetest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/script/interpreter"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKey struct {
+	wif     string
+	address string
+}
+
+func newTestKey(t *testing.T) testKey {
+	privateKey, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	require.NoError(t, err)
+
+	return testKey{wif: privateKey.Wif(), address: address.AddressString}
+}
+
+func newTestServer(t *testing.T) (*mneetest.Server, *mnee.MNEE) {
+	server := mneetest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := server.Client(mnee.WithRetryPolicy(mnee.RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+	require.NoError(t, err)
+
+	return server, client
+}
+
+func TestServer_SynchronousTransfer(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client := newTestServer(t)
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+
+	funding, err := server.Fund(sender.address, 3000)
+	require.NoError(t, err)
+	_, err = server.Fund(sender.address, 5000)
+	require.NoError(t, err)
+
+	t.Log("Test Case 1: The transfer is cosigned and the ledger updated...")
+	response, err := client.SynchronousTransfer(context.Background(), []string{sender.wif},
+		[]mnee.TransferMneeDTO{{Address: recipient.address, Amount: 4000}}, false, nil)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(3900), server.Balance(sender.address))
+	assertions.Equal(uint64(4000), server.Balance(recipient.address))
+	assertions.Equal(uint64(100), server.Balance(*server.Config.FeeAddress))
+
+	balances, err := client.GetBalances(context.Background(), []string{recipient.address})
+	assertions.NoError(err)
+	assertions.Equal(0.04, balances[0].Precised)
+
+	t.Log("Test Case 2: Every input carries valid owner and approver signatures, plus a funding input...")
+	cosigned, err := transaction.NewTransactionFromHex(*response.Txhex)
+	if !assertions.NoError(err) || !assertions.Len(cosigned.Inputs, 3) {
+		return
+	}
+	approverAddress, err := script.NewAddressFromPublicKey(server.Approver.PubKey(), true)
+	require.NoError(t, err)
+	fundingScript, err := p2pkh.Lock(approverAddress)
+	require.NoError(t, err)
+	for vin, input := range cosigned.Inputs {
+		sourceOutput := &transaction.TransactionOutput{Satoshis: 1000, LockingScript: fundingScript}
+		if vin < len(cosigned.Inputs)-1 {
+			txo, err := client.GetTxo(context.Background(), input.SourceTXID.String()+"_0")
+			if !assertions.NoError(err) {
+				return
+			}
+			scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+			if !assertions.NoError(err) {
+				return
+			}
+			sourceOutput = &transaction.TransactionOutput{Satoshis: uint64(txo.Satoshis), LockingScript: script.NewFromBytes(scriptBytes)}
+		}
+		err = interpreter.NewEngine().Execute(
+			interpreter.WithTx(cosigned, vin, sourceOutput),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		)
+		assertions.NoError(err, "input %d should verify", vin)
+	}
+
+	t.Log("Test Case 3: Lookups, history and parsing see the transfer...")
+	txHex, err := client.GetMNEETxHex(context.Background(), *response.Txid)
+	assertions.NoError(err)
+	assertions.Equal(*response.Txhex, *txHex)
+
+	history, err := client.GetSpecificTransactionHistory(context.Background(), []string{recipient.address}, 0, 10)
+	assertions.NoError(err)
+	if assertions.Len(history, 1) {
+		assertions.Equal(*response.Txid, *history[0].Txid)
+	}
+
+	parsed, err := client.ParseTransaction(context.Background(), *response.Txhex)
+	if assertions.NoError(err) {
+		assertions.Equal(int64(4000), parsed.NetAmounts[recipient.address])
+	}
+
+	t.Log("Test Case 4: Spent UTXOs cannot be spent again...")
+	_, err = client.SynchronousTransfer(context.Background(), []string{sender.wif},
+		[]mnee.TransferMneeDTO{{Address: recipient.address, Amount: 1000}}, true, []mnee.MneeTxo{funding})
+	var apiError *mnee.APIError
+	if assertions.ErrorAs(err, &apiError) {
+		assertions.Equal(http.StatusBadRequest, apiError.StatusCode)
+		assertions.Contains(apiError.Message, "already spent")
+	}
+
+	t.Log("Test Case 5: A transfer is only known by its cosigned txid and cannot be resubmitted...")
+	signed, _, err := client.NewTransferBuilder().WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 1000).Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	landed, err := client.SubmitRawTxSync(context.Background(), signed.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.NotEqual(signed.TxID().String(), *landed.Txid)
+
+	_, err = client.GetMNEETxHex(context.Background(), signed.TxID().String())
+	assertions.ErrorIs(err, mnee.ErrNotFound)
+
+	_, err = client.SubmitRawTxSync(context.Background(), signed.Hex())
+	if assertions.ErrorAs(err, &apiError) {
+		assertions.Equal(http.StatusBadRequest, apiError.StatusCode)
+		assertions.Contains(apiError.Message, "already spent")
+	}
+
+	t.Log("Test Case 6: A safe retry recognises the landed transfer despite the funding input...")
+	safe, err := server.Client(mnee.WithRetryPolicy(mnee.RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		SafeSubmit:           true,
+	}))
+	require.NoError(t, err)
+	server.InjectFault(mneetest.EndpointTransfer, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	resent, err := safe.SubmitRawTxSync(context.Background(), signed.Hex())
+	if assertions.NoError(err) {
+		assertions.Equal(*landed.Txid, *resent.Txid)
+	}
+
+	t.Log("Test Case 7: A fee that does not match the tier is rejected...")
+	_, err = server.Fund(sender.address, 5000)
+	require.NoError(t, err)
+	_, err = client.NewTransferBuilder().WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 1000).
+		WithFeePolicy(func(fees []mnee.Fee, amount uint64) (mnee.Fee, bool) {
+			return mnee.Fee{MinAmt: 0, MaxAmt: amount, Fee: 50}, true
+		}).
+		SubmitSync(context.Background())
+	if assertions.ErrorAs(err, &apiError) {
+		assertions.Equal(http.StatusBadRequest, apiError.StatusCode)
+		assertions.Contains(apiError.Message, "requires 100")
+	}
+}
+
+func TestServer_AsynchronousTransfer(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client := newTestServer(t)
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	_, err := server.Fund(sender.address, 5000)
+	require.NoError(t, err)
+
+	delivered := make(chan *mnee.Ticket, 2)
+	handler, err := mnee.NewWebhookHandler("s3cret")
+	require.NoError(t, err)
+	handler.OnSuccess = func(ctx context.Context, ticket *mnee.Ticket) error {
+		delivered <- ticket
+		return nil
+	}
+	webhook := httptest.NewServer(handler)
+	defer webhook.Close()
+
+	t.Log("Test Case 1: The ticket moves from BROADCASTING to SUCCESS...")
+	callbackURL, callbackSecret := webhook.URL, "s3cret"
+	ticketID, err := client.AsynchronousTransfer(context.Background(), []string{sender.wif},
+		[]mnee.TransferMneeDTO{{Address: recipient.address, Amount: 1000}}, false, nil, &callbackURL, &callbackSecret)
+	if !assertions.NoError(err) {
+		return
+	}
+
+	var statuses []mnee.TicketStatus
+	ticket, err := client.WaitForTicket(context.Background(), *ticketID, mnee.WaitOptions{
+		InitialInterval: time.Millisecond,
+		OnStatus: func(ticket *mnee.Ticket) {
+			statuses = append(statuses, ticket.Status)
+		},
+	})
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal([]mnee.TicketStatus{mnee.BROADCASTING, mnee.SUCCESS}, statuses)
+	assertions.Equal(uint64(1000), server.Balance(recipient.address))
+
+	t.Log("Test Case 2: The success callback reaches the webhook...")
+	select {
+	case callback := <-delivered:
+		assertions.Equal(*ticket.TxID, *callback.TxID)
+	case <-time.After(time.Second):
+		assertions.Fail("the success callback was not delivered")
+	}
+
+	t.Log("Test Case 3: Overridden ticket statuses fail the wait...")
+	_, err = server.Fund(sender.address, 5000)
+	require.NoError(t, err)
+	ticketID, err = client.AsynchronousTransfer(context.Background(), []string{sender.wif},
+		[]mnee.TransferMneeDTO{{Address: recipient.address, Amount: 1000}}, false, nil, nil, nil)
+	if !assertions.NoError(err) {
+		return
+	}
+	server.SetTicketStatus(*ticketID, mnee.FAILED, "mempool conflict")
+	_, err = client.WaitForTicket(context.Background(), *ticketID, mnee.WaitOptions{InitialInterval: time.Millisecond})
+	assertions.ErrorIs(err, mnee.ErrTicketFailed)
+}
+
+func TestServer_FaultsAndLatency(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client := newTestServer(t)
+	address := newTestKey(t).address
+
+	t.Log("Test Case 1: Transient faults are retried...")
+	server.InjectFault(mneetest.EndpointBalance,
+		mneetest.Fault{StatusCode: http.StatusServiceUnavailable},
+		mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	_, err := client.GetBalances(context.Background(), []string{address})
+	assertions.NoError(err)
+	assertions.Equal(3, server.Calls(mneetest.EndpointBalance))
+
+	t.Log("Test Case 2: Permanent faults surface as typed errors...")
+	server.InjectFault(mneetest.EndpointConfig, mneetest.Fault{StatusCode: http.StatusForbidden})
+	_, err = client.GetConfig(context.Background())
+	assertions.ErrorIs(err, mnee.ErrForbidden)
+
+	t.Log("Test Case 3: Latency trips client timeouts...")
+	server.SetLatency(mneetest.EndpointTxos, 200*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetTxo(ctx, strings.Repeat("0", 64)+"_0")
+	assertions.True(errors.Is(err, context.DeadlineExceeded), "expected a deadline error, got %v", err)
+
+	t.Log("Test Case 4: Unknown tokens are forbidden...")
+	other, err := mnee.NewMneeInstance(mnee.EnvCustom, "wrong", mnee.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, err = other.GetConfig(context.Background())
+	assertions.ErrorIs(err, mnee.ErrForbidden)
+}
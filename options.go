@@ -0,0 +1,79 @@
+package mnee
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option customises a MNEE client created by NewMneeInstance.
+type Option func(*MNEE) error
+
+// WithBaseURL overrides the API base URL chosen by the environment.
+// It is required for `EnvCustom` and can be used to point the SDK at a
+// staging proxy or a local stand-in server.
+func WithBaseURL(baseURL string) Option {
+	return func(m *MNEE) error {
+		parsedURL, err := url.Parse(baseURL)
+		if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+			return ErrInvalidBaseURL
+		}
+
+		m.mneeURL = strings.TrimRight(baseURL, "/")
+
+		return nil
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (which disables keep-alives)
+// with the provided one, allowing callers to reuse a tuned transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(m *MNEE) error {
+		if httpClient == nil {
+			return ErrNilHTTPClient
+		}
+
+		m.httpClient = httpClient
+
+		return nil
+	}
+}
+
+// WithTimeout sets the overall timeout applied to every HTTP request.
+// The provided HTTP client (if any) is copied, never modified in place.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *MNEE) error {
+		m.timeout = timeout
+
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(m *MNEE) error {
+		if userAgent != "" {
+			m.userAgent = userAgent
+		}
+
+		return nil
+	}
+}
+
+// WithLogger logs every API request (method, endpoint, status, duration and
+// size) and every stage of a transfer to the logger. Requests and transfer
+// stages are logged at Debug, submissions and retries at Info and failures at
+// Warn. The auth token is never logged. A nil logger disables logging, the default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *MNEE) error {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+
+		m.logger = logger
+
+		return nil
+	}
+}
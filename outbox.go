@@ -0,0 +1,500 @@
+package mnee
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// TransferRecordStatus is the state of a transfer in a TransferStore.
+type TransferRecordStatus string
+
+const (
+	// TransferPending transfers were recorded but their submission has no known outcome yet.
+	TransferPending TransferRecordStatus = "PENDING"
+	// TransferSubmitted transfers were accepted by the asynchronous endpoint and have a ticket.
+	TransferSubmitted TransferRecordStatus = "SUBMITTED"
+	// TransferSucceeded transfers were cosigned. It is terminal.
+	TransferSucceeded TransferRecordStatus = "SUCCESS"
+	// TransferFailed transfers were rejected and did not land. It is terminal.
+	TransferFailed TransferRecordStatus = "FAILED"
+)
+
+// TransferRecord is a transfer tracked by a TransferStore.
+type TransferRecord struct {
+	// ID is the txid of the transaction as submitted. It only keys the record:
+	// the cosigner adds its signatures, so the transaction lands under Txid.
+	ID string `json:"id"`
+	// RawTx is the hex of the transaction as submitted.
+	RawTx string `json:"rawtx"`
+	// Async is true for transfers submitted to the asynchronous endpoint.
+	Async       bool    `json:"async"`
+	CallbackURL *string `json:"callbackUrl,omitempty"`
+	// CallbackSecret is kept in plaintext so that Resume can submit the transfer
+	// again with the same callback. It authenticates webhook callbacks: stores
+	// holding asynchronous transfers with a callback hold secret material.
+	CallbackSecret *string `json:"callbackSecret,omitempty"`
+	TicketID       string  `json:"ticketId,omitempty"`
+	// Txid is the txid of the cosigned transaction once it succeeded.
+	Txid      string               `json:"txid,omitempty"`
+	Status    TransferRecordStatus `json:"status"`
+	Error     string               `json:"error,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// TransferStore persists transfers around their submission. With
+// WithTransferStore every transfer is saved before it is sent, so Resume can
+// settle the ones whose outcome was lost to a crash. Records carry the webhook
+// CallbackSecret of asynchronous transfers, so implementations must protect
+// them like any other secret (access control, encryption at rest).
+type TransferStore interface {
+	// Save inserts the record, or replaces the record with the same ID.
+	Save(ctx context.Context, record TransferRecord) error
+	// Get returns the record with the ID, or ErrNotFound.
+	Get(ctx context.Context, id string) (*TransferRecord, error)
+	// Pending returns the records that are neither SUCCESS nor FAILED, oldest first.
+	Pending(ctx context.Context) ([]TransferRecord, error)
+}
+
+// WithTransferStore records every transfer submitted by the client (transfer
+// functions, TransferBuilder and SubmitRawTx*) in the store before sending it.
+func WithTransferStore(store TransferStore) Option {
+	return func(m *MNEE) error {
+		m.transferStore = store
+
+		return nil
+	}
+}
+
+// MemoryTransferStore is a TransferStore kept in memory, for tests and for
+// services that only need to survive failed requests rather than crashes.
+type MemoryTransferStore struct {
+	mutex   sync.Mutex
+	records map[string]TransferRecord
+}
+
+// NewMemoryTransferStore returns an empty MemoryTransferStore.
+func NewMemoryTransferStore() *MemoryTransferStore {
+	return &MemoryTransferStore{records: make(map[string]TransferRecord)}
+}
+
+// Save implements TransferStore.
+func (s *MemoryTransferStore) Save(ctx context.Context, record TransferRecord) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.records[record.ID] = record
+	return nil
+}
+
+// Get implements TransferStore.
+func (s *MemoryTransferStore) Get(ctx context.Context, id string) (*TransferRecord, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &record, nil
+}
+
+// Pending implements TransferStore.
+func (s *MemoryTransferStore) Pending(ctx context.Context) ([]TransferRecord, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return pendingRecords(s.records), nil
+}
+
+// JSONLinesTransferStore is a TransferStore backed by an append-only file of
+// JSON lines, one per saved record; the last line of a record wins. Every Save
+// is synced to disk before it returns. A line torn by a crash is ignored. The
+// file is created with mode 0600 since it holds callback secrets in plaintext.
+type JSONLinesTransferStore struct {
+	mutex   sync.Mutex
+	file    *os.File
+	records map[string]TransferRecord
+}
+
+// NewJSONLinesTransferStore opens (or creates) the store at path and replays it.
+// The caller must Close it.
+func NewJSONLinesTransferStore(path string) (*JSONLinesTransferStore, error) {
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	var store JSONLinesTransferStore = JSONLinesTransferStore{file: file, records: make(map[string]TransferRecord)}
+	var scanner *bufio.Scanner = bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record TransferRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil || record.ID == "" {
+			continue
+		}
+
+		store.records[record.ID] = record
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading transfer store: %w", err)
+	}
+
+	return &store, nil
+}
+
+// Save implements TransferStore.
+func (s *JSONLinesTransferStore) Save(ctx context.Context, record TransferRecord) error {
+
+	line, err := json.Marshal(&record)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// A leading newline terminates any line torn by an earlier crash.
+	_, err = s.file.Write(append(append([]byte("\n"), line...), '\n'))
+	if err != nil {
+		return err
+	}
+
+	err = s.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	s.records[record.ID] = record
+	return nil
+}
+
+// Get implements TransferStore.
+func (s *JSONLinesTransferStore) Get(ctx context.Context, id string) (*TransferRecord, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &record, nil
+}
+
+// Pending implements TransferStore.
+func (s *JSONLinesTransferStore) Pending(ctx context.Context) ([]TransferRecord, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return pendingRecords(s.records), nil
+}
+
+// Close closes the underlying file.
+func (s *JSONLinesTransferStore) Close() error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.file.Close()
+}
+
+// Resume settles the pending transfers of the client's TransferStore and
+// returns them in their new state. A PENDING asynchronous transfer with a
+// ticket ID is settled from its ticket when the cosigner knows it. Otherwise a
+// PENDING transfer is checked through its inputs: if the cosigned transaction spent them it is marked SUCCESS with the
+// cosigned txid, if another transaction spent them it is marked FAILED, and if
+// they are still unspent it is submitted again. Transfers whose inputs are spent
+// by a transaction not indexed yet stay PENDING. A SUBMITTED transfer is settled
+// from its ticket; tickets still in progress stay SUBMITTED.
+//
+// Call it at startup, and again until no record stays pending.
+func (m *MNEE) Resume(ctx context.Context) (_ []TransferRecord, err error) {
+
+	ctx, span := m.startSpan(ctx, "Resume")
+	defer func() { span.End(err) }()
+
+	if m.transferStore == nil {
+		return nil, ErrNoTransferStore
+	}
+
+	records, err := m.transferStore.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		err = m.reconcile(ctx, &records[i])
+		if err != nil {
+			return records, fmt.Errorf("resuming transfer %s: %w", records[i].ID, err)
+		}
+	}
+
+	return records, nil
+}
+
+// submitSync records the transaction in the transfer store, if any, and posts
+// it to the synchronous endpoint, recording the outcome.
+func (m *MNEE) submitSync(ctx context.Context, txBytes []byte) (*TransferResponseDTO, error) {
+
+	var record TransferRecord = newTransferRecord(txBytes, false, nil, nil)
+	if m.transferStore != nil {
+		err := m.transferStore.Save(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("recording transfer: %w", err)
+		}
+	}
+
+	response, err := m.postSync(ctx, txBytes)
+	if m.transferStore != nil {
+		m.recordOutcome(ctx, &record, response, nil, err)
+	}
+
+	var result slog.Attr = slog.String("cosigned_txid", "")
+	if response != nil && response.Txid != nil {
+		result = slog.String("cosigned_txid", *response.Txid)
+	}
+	m.logSubmission(ctx, "sync", record.ID, result, err)
+
+	return response, err
+}
+
+// submitAsync records the transaction in the transfer store, if any, and
+// posts it to the asynchronous endpoint, recording the ticket.
+func (m *MNEE) submitAsync(ctx context.Context, txBytes []byte, callbackURL *string, callbackSecret *string) (*string, error) {
+
+	var record TransferRecord = newTransferRecord(txBytes, true, callbackURL, callbackSecret)
+	if m.transferStore != nil {
+		err := m.transferStore.Save(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("recording transfer: %w", err)
+		}
+	}
+
+	ticketID, err := m.postAsync(ctx, txBytes, callbackURL, callbackSecret)
+	if m.transferStore != nil {
+		m.recordOutcome(ctx, &record, nil, ticketID, err)
+	}
+
+	var result slog.Attr = slog.String("ticket_id", "")
+	if ticketID != nil {
+		result = slog.String("ticket_id", *ticketID)
+	}
+	m.logSubmission(ctx, "async", record.ID, result, err)
+
+	return ticketID, err
+}
+
+// logSubmission logs a transfer submission at Info, or at Warn when it failed,
+// and adds its txid and result to the current span.
+func (m *MNEE) logSubmission(ctx context.Context, mode string, txid string, result slog.Attr, err error) {
+
+	spanFromContext(ctx).SetAttributes(Attribute{Key: "mnee.txid", Value: txid},
+		Attribute{Key: "mnee." + result.Key, Value: result.Value.String()})
+
+	if err != nil {
+		m.logger.LogAttrs(ctx, slog.LevelWarn, "mnee transfer submission failed",
+			slog.String("mode", mode), slog.String("txid", txid), slog.String("error", err.Error()))
+		return
+	}
+
+	m.logger.LogAttrs(ctx, slog.LevelInfo, "mnee transfer submitted",
+		slog.String("mode", mode), slog.String("txid", txid), result)
+}
+
+// reconcile settles one pending record against the API and saves it.
+func (m *MNEE) reconcile(ctx context.Context, record *TransferRecord) error {
+
+	if record.Status == TransferPending && record.Async && record.TicketID != "" {
+		// The ticket was accepted, so its inputs may not be spent yet: posting
+		// the transfer again would open a second ticket.
+		settled, err := m.settleTicket(ctx, record)
+		if settled || err != nil {
+			return err
+		}
+	}
+
+	if record.Status == TransferPending {
+		submitted, err := transaction.NewTransactionFromHex(record.RawTx)
+		if err != nil {
+			record.Status, record.Error = TransferFailed, err.Error()
+			return m.saveRecord(ctx, record)
+		}
+
+		settled, err := m.settleLanding(ctx, record, submitted)
+		if settled || err != nil {
+			return err
+		}
+
+		var response *TransferResponseDTO
+		var ticketID *string
+		if record.Async {
+			ticketID, err = m.postAsync(ctx, submitted.Bytes(), record.CallbackURL, record.CallbackSecret)
+		} else {
+			response, err = m.postSync(ctx, submitted.Bytes())
+		}
+
+		if isRejected(err) {
+			// The inputs may be spent because the transfer landed in the meantime.
+			settled, lookupErr := m.settleLanding(ctx, record, submitted)
+			if settled || lookupErr != nil {
+				return lookupErr
+			}
+		}
+
+		m.recordOutcome(ctx, record, response, ticketID, err)
+		if err != nil && !isRejected(err) {
+			return err
+		}
+	}
+
+	if record.Status == TransferSubmitted {
+		_, err := m.settleTicket(ctx, record)
+		return err
+	}
+
+	return nil
+}
+
+// settleTicket settles a record from its ticket and saves it: SUCCESS or FAILED
+// once the ticket is terminal, SUBMITTED while it is in progress. It reports
+// whether the cosigner knows the ticket.
+func (m *MNEE) settleTicket(ctx context.Context, record *TransferRecord) (bool, error) {
+
+	ticket, err := m.getTicket(ctx, record.TicketID)
+	if isRecordNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case len(ticket.Errors) > 0 || ticket.Status == FAILED:
+		record.Status, record.Error = TransferFailed, strings.Join(ticket.Errors, "; ")
+	case ticket.Status == SUCCESS || ticket.Status == MINED:
+		record.Status = TransferSucceeded
+		if ticket.TxID != nil {
+			record.Txid = *ticket.TxID
+		}
+	case record.Status == TransferSubmitted:
+		return true, nil
+	default:
+		record.Status = TransferSubmitted
+	}
+
+	return true, m.saveRecord(ctx, record)
+}
+
+// recordOutcome saves the result of a submission. Errors other than a
+// rejection leave the record PENDING, since the transfer may have landed.
+// Failing to save is not reported: the record stays pending and Resume settles it.
+func (m *MNEE) recordOutcome(ctx context.Context, record *TransferRecord, response *TransferResponseDTO, ticketID *string, err error) {
+
+	switch {
+	case isRejected(err):
+		record.Status, record.Error = TransferFailed, err.Error()
+	case err != nil:
+		return
+	case ticketID != nil:
+		record.Status, record.TicketID = TransferSubmitted, *ticketID
+	default:
+		record.Status = TransferSucceeded
+		if response != nil && response.Txid != nil {
+			record.Txid = *response.Txid
+		}
+	}
+
+	_ = m.saveRecord(ctx, record)
+}
+
+// saveRecord stamps and saves a record.
+func (m *MNEE) saveRecord(ctx context.Context, record *TransferRecord) error {
+
+	record.UpdatedAt = time.Now()
+	return m.transferStore.Save(ctx, *record)
+}
+
+// settleLanding checks the inputs of a pending transfer and saves it as SUCCESS,
+// with the cosigned txid, when it landed, or as FAILED when another transaction
+// spent its inputs. It reports whether the record was settled or must stay
+// pending, i.e. whether it must not be submitted.
+func (m *MNEE) settleLanding(ctx context.Context, record *TransferRecord, submitted *transaction.Transaction) (bool, error) {
+
+	status, landed, err := m.landing(ctx, submitted)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case landingLanded:
+		record.Status, record.Txid = TransferSucceeded, landed.TxID().String()
+		return true, m.saveRecord(ctx, record)
+	case landingConflict:
+		record.Status, record.Error = TransferFailed, ErrInputsSpent.Error()
+		return true, m.saveRecord(ctx, record)
+	case landingUnknown:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// newTransferRecord returns the PENDING record of a transaction about to be submitted.
+func newTransferRecord(txBytes []byte, async bool, callbackURL *string, callbackSecret *string) TransferRecord {
+
+	var now time.Time = time.Now()
+	return TransferRecord{
+		ID:             chainhash.DoubleHashH(txBytes).String(),
+		RawTx:          hex.EncodeToString(txBytes),
+		Async:          async,
+		CallbackURL:    callbackURL,
+		CallbackSecret: callbackSecret,
+		Status:         TransferPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// pendingRecords returns the non-terminal records, oldest first.
+func pendingRecords(records map[string]TransferRecord) []TransferRecord {
+
+	var pending []TransferRecord = make([]TransferRecord, 0)
+	for _, record := range records {
+		if record.Status != TransferSucceeded && record.Status != TransferFailed {
+			pending = append(pending, record)
+		}
+	}
+
+	slices.SortFunc(pending, func(a, b TransferRecord) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return pending
+}
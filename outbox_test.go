@@ -0,0 +1,218 @@
+package mnee_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResume(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	server, plain, signer, sender := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 4, 100)
+	inputs := make([][]mnee.MneeTxo, 0, 4)
+	for range 4 {
+		txo, err := server.Fund(sender, 1000)
+		require.NoError(t, err)
+		inputs = append(inputs, []mnee.MneeTxo{txo})
+	}
+	path := filepath.Join(t.TempDir(), "transfers.jsonl")
+
+	store, err := mnee.NewJSONLinesTransferStore(path)
+	require.NoError(t, err)
+	client, err := server.Client(mnee.WithRetryPolicy(mnee.RetryPolicy{MaxAttempts: 1}), mnee.WithTransferStore(store))
+	require.NoError(t, err)
+
+	t.Log("Test Case 1: Transfers whose submission failed stay pending...")
+	server.InjectFault(mneetest.EndpointTransfer, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	_, err = client.NewTransferBuilder().WithSigner(signer).WithInputs(inputs[0]).AddRecipients(recipients[0]).SubmitSync(ctx)
+	assertions.Error(err)
+	server.InjectFault(mneetest.EndpointTransferAsync, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	_, err = client.NewTransferBuilder().WithSigner(signer).WithInputs(inputs[1]).AddRecipients(recipients[1]).SubmitAsync(ctx, nil, nil)
+	assertions.Error(err)
+
+	pending, err := store.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assertions.Equal(mnee.TransferPending, pending[0].Status)
+	assertions.False(pending[0].Async)
+	assertions.True(pending[1].Async)
+
+	t.Log("Test Case 2: A successful transfer is recorded as SUCCESS...")
+	server.InjectFault(mneetest.EndpointTransfer, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	tx, _, err := client.NewTransferBuilder().WithSigner(signer).WithInputs(inputs[2]).AddRecipients(recipients[2]).Sign(ctx)
+	require.NoError(t, err)
+	_, err = client.SubmitRawTxSync(ctx, tx.Hex())
+	assertions.Error(err)
+	// The transfer lands through another client before the outcome is known.
+	response, err := plain.SubmitRawTxSync(ctx, tx.Hex())
+	require.NoError(t, err)
+	assertions.NoError(store.Close())
+
+	t.Log("Test Case 3: Resume after a restart settles every pending transfer...")
+	store, err = mnee.NewJSONLinesTransferStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	client, err = server.Client(mnee.WithTransferStore(store))
+	require.NoError(t, err)
+
+	submissions := server.Calls(mneetest.EndpointTransfer) + server.Calls(mneetest.EndpointTransferAsync)
+	records, err := client.Resume(ctx)
+	if !assertions.NoError(err) {
+		return
+	}
+	require.Len(t, records, 3)
+	assertions.Equal(mnee.TransferSucceeded, records[0].Status)
+	assertions.NotEmpty(records[0].Txid)
+	assertions.Equal(mnee.TransferSubmitted, records[1].Status, "the ticket was still broadcasting")
+	assertions.NotEmpty(records[1].TicketID)
+	assertions.Equal(mnee.TransferSucceeded, records[2].Status)
+	assertions.Equal(*response.Txid, records[2].Txid)
+	assertions.NotEqual(records[2].ID, records[2].Txid, "the cosigner changes the txid")
+	assertions.Equal(submissions+2, server.Calls(mneetest.EndpointTransfer)+server.Calls(mneetest.EndpointTransferAsync),
+		"the landed transfer was not submitted again")
+
+	records, err = client.Resume(ctx)
+	assertions.NoError(err)
+	require.Len(t, records, 1)
+	assertions.Equal(mnee.TransferSucceeded, records[0].Status)
+	assertions.NotEmpty(records[0].Txid)
+
+	pending, err = store.Pending(ctx)
+	assertions.NoError(err)
+	assertions.Empty(pending)
+	for _, recipient := range recipients[:3] {
+		assertions.Equal(uint64(100), server.Balance(recipient.Address))
+	}
+
+	t.Log("Test Case 4: A transfer whose inputs were spent elsewhere ends FAILED...")
+	server.InjectFault(mneetest.EndpointTransfer, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	_, err = client.NewTransferBuilder().WithSigner(signer).WithInputs(inputs[3]).AddRecipients(recipients[3]).SubmitSync(ctx)
+	assertions.Error(err)
+	_, err = plain.NewTransferBuilder().WithSigner(signer).WithInputs(inputs[3]).AddRecipients(recipients[0]).SubmitSync(ctx)
+	require.NoError(t, err)
+
+	records, err = client.Resume(ctx)
+	assertions.NoError(err)
+	require.Len(t, records, 1)
+	assertions.Equal(mnee.TransferFailed, records[0].Status)
+	assertions.Equal(mnee.ErrInputsSpent.Error(), records[0].Error)
+	assertions.Empty(records[0].Txid)
+	assertions.Zero(server.Balance(recipients[3].Address))
+
+	t.Log("Test Case 5: Resume needs a transfer store...")
+	_, err = plain.Resume(ctx)
+	assertions.ErrorIs(err, mnee.ErrNoTransferStore)
+}
+
+func TestResume_PendingTicket(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	server, plain, signer, sender := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 2, 100)
+	txo, err := server.Fund(sender, 1000)
+	require.NoError(t, err)
+
+	store := mnee.NewMemoryTransferStore()
+	client, err := server.Client(mnee.WithTransferStore(store))
+	require.NoError(t, err)
+
+	// A ticket accepted before a crash, whose SUBMITTED state was never saved
+	// and whose inputs are not spent yet.
+	tx, _, err := client.NewTransferBuilder().WithSigner(signer).WithInputs([]mnee.MneeTxo{txo}).AddRecipients(recipients[0]).Sign(ctx)
+	require.NoError(t, err)
+	ticketID, err := plain.NewTransferBuilder().WithSigner(signer).AddRecipients(recipients[1]).SubmitAsync(ctx, nil, nil)
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, mnee.TransferRecord{
+		ID:        tx.TxID().String(),
+		RawTx:     tx.Hex(),
+		Async:     true,
+		TicketID:  *ticketID,
+		Status:    mnee.TransferPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}))
+
+	t.Log("Test Case 1: A PENDING transfer with a ticket in progress is not posted again...")
+	submissions := server.Calls(mneetest.EndpointTransferAsync)
+	records, err := client.Resume(ctx)
+	if !assertions.NoError(err) {
+		return
+	}
+	require.Len(t, records, 1)
+	assertions.Equal(mnee.TransferSubmitted, records[0].Status)
+	assertions.Equal(*ticketID, records[0].TicketID)
+	assertions.Equal(submissions, server.Calls(mneetest.EndpointTransferAsync))
+	assertions.Zero(server.Balance(recipients[0].Address))
+
+	t.Log("Test Case 2: It is settled from its ticket...")
+	server.SetTicketStatus(*ticketID, mnee.SUCCESS)
+	records, err = client.Resume(ctx)
+	assertions.NoError(err)
+	require.Len(t, records, 1)
+	assertions.Equal(mnee.TransferSucceeded, records[0].Status)
+	assertions.NotEmpty(records[0].Txid)
+	assertions.Equal(submissions, server.Calls(mneetest.EndpointTransferAsync))
+}
+
+func TestJSONLinesTransferStore(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "transfers.jsonl")
+
+	store, err := mnee.NewJSONLinesTransferStore(path)
+	require.NoError(t, err)
+
+	t.Log("Test Case 1: The last save of a record wins...")
+	assertions.NoError(store.Save(ctx, mnee.TransferRecord{ID: "a", Status: mnee.TransferPending}))
+	assertions.NoError(store.Save(ctx, mnee.TransferRecord{ID: "b", Status: mnee.TransferPending}))
+	assertions.NoError(store.Save(ctx, mnee.TransferRecord{ID: "a", Status: mnee.TransferSucceeded, Txid: "txid"}))
+	assertions.NoError(store.Close())
+
+	t.Log("Test Case 2: Reopening replays the file and ignores a torn line...")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":"c","sta`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	store, err = mnee.NewJSONLinesTransferStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	record, err := store.Get(ctx, "a")
+	if assertions.NoError(err) {
+		assertions.Equal(mnee.TransferSucceeded, record.Status)
+		assertions.Equal("txid", record.Txid)
+	}
+	_, err = store.Get(ctx, "c")
+	assertions.ErrorIs(err, mnee.ErrNotFound)
+
+	pending, err := store.Pending(ctx)
+	assertions.NoError(err)
+	if assertions.Len(pending, 1) {
+		assertions.Equal("b", pending[0].ID)
+	}
+
+	t.Log("Test Case 3: Saves after a torn line are read back...")
+	assertions.NoError(store.Save(ctx, mnee.TransferRecord{ID: "c", Status: mnee.TransferFailed}))
+	reopened, err := mnee.NewJSONLinesTransferStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	record, err = reopened.Get(ctx, "c")
+	if assertions.NoError(err) {
+		assertions.Equal(mnee.TransferFailed, record.Status)
+	}
+}
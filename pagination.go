@@ -0,0 +1,124 @@
+package mnee
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultPageSize is the page size used by AllUnspentTxos and AllHistory when
+// PageOptions.Size is not set.
+const DefaultPageSize int = 100
+
+// PageOptions configures the iterators returned by AllUnspentTxos and AllHistory.
+type PageOptions struct {
+	// Size is the number of items requested per page. Defaults to DefaultPageSize.
+	Size int
+	// Prefetch requests the next page concurrently while the current one is consumed.
+	Prefetch bool
+}
+
+// AllUnspentTxos iterates over every MNEE UTXO of the addresses, fetching them
+// page by page with GetPaginatedUnspentTxos. Iteration stops at the first
+// error, which is yielded with a zero MneeTxo, including context cancellation.
+//
+//	for txo, err := range m.AllUnspentTxos(ctx, addresses, mnee.PageOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (m *MNEE) AllUnspentTxos(ctx context.Context, addresses []string, opts PageOptions) iter.Seq2[MneeTxo, error] {
+
+	var size int = opts.pageSize()
+	return paginate(ctx, size, opts.Prefetch, func(ctx context.Context, page int) ([]MneeTxo, error) {
+		// The UTXO endpoint numbers pages from 1.
+		return m.GetPaginatedUnspentTxos(ctx, addresses, page+1, size)
+	})
+}
+
+// AllHistory iterates over the transaction history of the addresses, fetching
+// it page by page with GetSpecificTransactionHistory. Errors are yielded like
+// in AllUnspentTxos.
+func (m *MNEE) AllHistory(ctx context.Context, addresses []string, opts PageOptions) iter.Seq2[TransactionHistoryDTO, error] {
+
+	var size int = opts.pageSize()
+	return paginate(ctx, size, opts.Prefetch, func(ctx context.Context, page int) ([]TransactionHistoryDTO, error) {
+		return m.GetSpecificTransactionHistory(ctx, addresses, page*size, size)
+	})
+}
+
+// pageSize returns the configured page size or DefaultPageSize.
+func (o PageOptions) pageSize() int {
+
+	if o.Size <= 0 {
+		return DefaultPageSize
+	}
+
+	return o.Size
+}
+
+// pageResult is a fetched page, or the error fetching it.
+type pageResult[T any] struct {
+	items []T
+	err   error
+}
+
+// paginate yields the items of 0-based pages returned by fetch until a page
+// holds fewer than `size` items. With prefetch, page n+1 is requested while
+// page n is being yielded; it is cancelled if the caller stops early.
+func paginate[T any](ctx context.Context, size int, prefetch bool, fetch func(ctx context.Context, page int) ([]T, error)) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var zero T
+		var pending chan pageResult[T]
+		for page := 0; ; page++ {
+			err := ctx.Err()
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var result pageResult[T]
+			if pending != nil {
+				select {
+				case result = <-pending:
+				case <-ctx.Done():
+					yield(zero, ctx.Err())
+					return
+				}
+				pending = nil
+			} else {
+				result.items, result.err = fetch(ctx, page)
+			}
+
+			if result.err != nil {
+				yield(zero, result.err)
+				return
+			}
+
+			var last bool = len(result.items) < size
+			if prefetch && !last {
+				// Buffered so the goroutine never blocks if the iteration stops early.
+				pending = make(chan pageResult[T], 1)
+				go func(page int, pending chan<- pageResult[T]) {
+					items, err := fetch(ctx, page)
+					pending <- pageResult[T]{items: items, err: err}
+				}(page+1, pending)
+			}
+
+			for _, item := range result.items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if last {
+				return
+			}
+		}
+	}
+}
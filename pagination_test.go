@@ -0,0 +1,129 @@
+package mnee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagingFixture serves `count` UTXOs on /v2/utxos and `count` history
+// entries on /v1/sync, failing requests for pages at or after `failPage`.
+func newPagingFixture(t *testing.T, count int, failPage int) (*MNEE, *atomic.Int32) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		var start, size int
+		switch r.URL.Path {
+		case "/v2/utxos":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			size, _ = strconv.Atoi(r.URL.Query().Get("size"))
+			start = (page - 1) * size
+		case "/v1/sync":
+			start, _ = strconv.Atoi(r.URL.Query().Get("from"))
+			size, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+		}
+
+		if failPage > 0 && start/size+1 >= failPage {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		txos := make([]MneeTxo, 0)
+		history := make([]TransactionHistoryDTO, 0)
+		for i := start; i < min(start+size, count); i++ {
+			outpoint := fmt.Sprintf("%064d_0", i)
+			txos = append(txos, MneeTxo{Outpoint: &outpoint, Score: uint64(i)})
+			history = append(history, TransactionHistoryDTO{Score: uint64(i)})
+		}
+
+		if r.URL.Path == "/v2/utxos" {
+			_ = json.NewEncoder(w).Encode(txos)
+		} else {
+			_ = json.NewEncoder(w).Encode(history)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+
+	return m, &calls
+}
+
+func TestAllUnspentTxos(t *testing.T) {
+	assertions := assert.New(t)
+
+	for _, prefetch := range []bool{false, true} {
+		t.Logf("Test Case 1 (prefetch=%v): Every page is fetched in order...", prefetch)
+		m, calls := newPagingFixture(t, 7, 0)
+		var scores []uint64
+		for txo, err := range m.AllUnspentTxos(context.Background(), []string{"address"}, PageOptions{Size: 3, Prefetch: prefetch}) {
+			if !assertions.NoError(err) {
+				break
+			}
+			scores = append(scores, txo.Score)
+		}
+		assertions.Equal([]uint64{0, 1, 2, 3, 4, 5, 6}, scores)
+		assertions.Equal(int32(3), calls.Load())
+
+		t.Logf("Test Case 2 (prefetch=%v): Stopping early stops paging...", prefetch)
+		m, calls = newPagingFixture(t, 100, 0)
+		var seen int
+		for _, err := range m.AllUnspentTxos(context.Background(), []string{"address"}, PageOptions{Size: 5, Prefetch: prefetch}) {
+			assertions.NoError(err)
+			seen++
+			if seen == 6 {
+				break
+			}
+		}
+		assertions.Equal(6, seen)
+		assertions.LessOrEqual(calls.Load(), int32(3))
+	}
+
+	t.Log("Test Case 3: Errors end the iteration after the pages already fetched...")
+	m, _ := newPagingFixture(t, 10, 2)
+	var items int
+	var iterationErr error
+	for _, err := range m.AllUnspentTxos(context.Background(), []string{"address"}, PageOptions{Size: 4, Prefetch: true}) {
+		if err != nil {
+			iterationErr = err
+			continue
+		}
+		items++
+	}
+	assertions.Equal(4, items)
+	assertions.ErrorIs(iterationErr, ErrNotFound)
+
+	t.Log("Test Case 4: A cancelled context is yielded as an error...")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range m.AllUnspentTxos(ctx, []string{"address"}, PageOptions{}) {
+		assertions.ErrorIs(err, context.Canceled)
+	}
+}
+
+func TestAllHistory(t *testing.T) {
+	assertions := assert.New(t)
+
+	t.Log("Test Case 1: History is paged by offset...")
+	m, calls := newPagingFixture(t, 6, 0)
+	var scores []uint64
+	for entry, err := range m.AllHistory(context.Background(), []string{"address"}, PageOptions{Size: 3, Prefetch: true}) {
+		if !assertions.NoError(err) {
+			break
+		}
+		scores = append(scores, entry.Score)
+	}
+	assertions.Equal([]uint64{0, 1, 2, 3, 4, 5}, scores)
+	assertions.Equal(int32(3), calls.Load(), "a full last page needs one more, empty, page")
+}
@@ -4,11 +4,78 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
 )
 
+// ParsedMneeInput is a decoded input of a MNEE transaction.
+type ParsedMneeInput struct {
+	Vin      int    `json:"vin"`
+	Outpoint string `json:"outpoint"`
+	// IsMnee is false for inputs the MNEE API does not know, such as the
+	// cosigner's funding inputs. Their Address and Amount are empty.
+	IsMnee  bool   `json:"isMnee"`
+	Address string `json:"address,omitempty"`
+	Amount  uint64 `json:"amount"`
+}
+
+// ParsedMneeOutput is a decoded MNEE output of a transaction.
+type ParsedMneeOutput struct {
+	Vout    int    `json:"vout"`
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+	// IsFee is true when the output pays SystemConfig.FeeAddress.
+	IsFee bool `json:"isFee"`
+	// IsChange is true when the output returns to one of the input addresses.
+	// A transfer to oneself is therefore reported as change.
+	IsChange bool `json:"isChange"`
+	// Action is the metadata action of mint, redeem and deploy inscriptions.
+	Action string `json:"action,omitempty"`
+}
+
+// ParsedMneeTx is a MNEE transaction decoded against the current system config.
+type ParsedMneeTx struct {
+	Txid string `json:"txid"`
+	// Operation is one of ACTION_TRANSFER, ACTION_MINT, ACTION_REDEEM or ACTION_DEPLOY.
+	Operation   string             `json:"operation"`
+	Inputs      []ParsedMneeInput  `json:"inputs"`
+	Outputs     []ParsedMneeOutput `json:"outputs"`
+	TotalInput  uint64             `json:"totalInput"`
+	TotalOutput uint64             `json:"totalOutput"`
+	Fee         uint64             `json:"fee"`
+	// NetAmounts is the MNEE received minus the MNEE spent, per address.
+	NetAmounts map[string]int64 `json:"netAmounts"`
+	// Decimals is the token's decimals, used by the *Amount accessors.
+	Decimals uint8 `json:"decimals"`
+}
+
+// TotalInputAmount returns TotalInput as an exact Amount.
+func (p *ParsedMneeTx) TotalInputAmount() Amount {
+	return NewAmount(p.TotalInput, p.Decimals)
+}
+
+// TotalOutputAmount returns TotalOutput as an exact Amount.
+func (p *ParsedMneeTx) TotalOutputAmount() Amount {
+	return NewAmount(p.TotalOutput, p.Decimals)
+}
+
+// FeeAmount returns Fee as an exact Amount.
+func (p *ParsedMneeTx) FeeAmount() Amount {
+	return NewAmount(p.Fee, p.Decimals)
+}
+
+// mneeScript is a MNEE output script decoded by parseMneeScript.
+type mneeScript struct {
+	address     string
+	amount      uint64
+	inscription DeployChainInscription
+}
+
 // IsMneeScript validates if a given ASM script is a valid MNEE token script.
 // It checks the script structure, MNEE-1SAT inscription, locking script,
 // and token details against the current MNEE system configuration.
@@ -20,57 +87,233 @@ func (m *MNEE) IsMneeScript(ctx context.Context, asmScript string) (bool, error)
 		return false, err
 	}
 
-	var scriptTokens []string = strings.Split(asmScript, " ")
-	if len(scriptTokens) != 13 && len(scriptTokens) != 15 {
-		return false, nil
+	_, valid := parseMneeScript(strings.Split(asmScript, " "), config)
+	return valid, nil
+}
+
+// ParseTransaction decodes every MNEE output of a raw transaction, resolves
+// its inputs with GetTxo and classifies the transaction. Outputs that are not
+// MNEE scripts (e.g. the cosigner's satoshi change) are left out. The
+// deploy+mint output whose outpoint is the configured token ID is decoded as
+// well and makes the transaction an ACTION_DEPLOY.
+func (m *MNEE) ParseTransaction(ctx context.Context, rawTxHex string) (_ *ParsedMneeTx, err error) {
+
+	ctx, span := m.startSpan(ctx, "ParseTransaction")
+	defer func() { span.End(err) }()
+
+	mneeTransaction, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil, err
 	}
 
-	var valid bool = validateOrdInscription(scriptTokens)
-	if !valid {
-		return false, nil
+	span.SetAttributes(Attribute{Key: "mnee.txid", Value: mneeTransaction.TxID().String()})
+
+	config, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(scriptTokens) == 13 {
-		p2pkhScript, err := script.NewFromASM(strings.Join(scriptTokens[8:], " "))
-		if err != nil {
-			return false, nil
+	var parsed ParsedMneeTx = ParsedMneeTx{
+		Txid:       mneeTransaction.TxID().String(),
+		Operation:  ACTION_TRANSFER,
+		Inputs:     make([]ParsedMneeInput, 0, len(mneeTransaction.Inputs)),
+		Outputs:    make([]ParsedMneeOutput, 0, len(mneeTransaction.Outputs)),
+		NetAmounts: make(map[string]int64),
+		Decimals:   config.Decimals,
+	}
+
+	var inputAddresses map[string]bool = make(map[string]bool)
+	for vin, input := range mneeTransaction.Inputs {
+		var parsedInput ParsedMneeInput = ParsedMneeInput{
+			Vin:      vin,
+			Outpoint: fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex),
 		}
 
-		if !p2pkhScript.IsP2PKH() {
-			return false, nil
+		txo, err := m.GetTxo(ctx, parsedInput.Outpoint)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
 		}
 
-		valid = validateTransferLockingScript(scriptTokens[8:], config)
-		if !valid {
-			return false, nil
+		if err == nil && len(txo.Owners) > 0 && txo.Data != nil && txo.Data.Bsv21 != nil {
+			parsedInput.IsMnee = true
+			parsedInput.Address = txo.Owners[0]
+			parsedInput.Amount = txoAmount(*txo)
+
+			inputAddresses[parsedInput.Address] = true
+			parsed.TotalInput += parsedInput.Amount
+			parsed.NetAmounts[parsedInput.Address] -= int64(parsedInput.Amount)
+
+			if config.MintAddress != nil && parsedInput.Address == *config.MintAddress {
+				parsed.Operation = ACTION_MINT
+			}
 		}
+
+		parsed.Inputs = append(parsed.Inputs, parsedInput)
 	}
 
-	if len(scriptTokens) == 15 {
-		valid = validateTransferLockingScript(scriptTokens[8:], config)
+	var action string
+	for vout, output := range mneeTransaction.Outputs {
+		var scriptTokens []string = strings.Split(output.LockingScript.ToASM(), " ")
+		decoded, valid := parseMneeScript(scriptTokens, config)
+		if !valid {
+			decoded, valid = parseMneeDeployScript(scriptTokens, config, fmt.Sprintf("%s_%d", parsed.Txid, vout))
+		}
 		if !valid {
-			return false, nil
+			continue
+		}
+
+		var parsedOutput ParsedMneeOutput = ParsedMneeOutput{
+			Vout:    vout,
+			Address: decoded.address,
+			Amount:  decoded.amount,
+			IsFee:   config.FeeAddress != nil && decoded.address == *config.FeeAddress,
+		}
+		parsedOutput.IsChange = !parsedOutput.IsFee && inputAddresses[decoded.address]
+
+		if decoded.inscription.Operation == DEPLOY_MINT {
+			parsedOutput.Action = ACTION_DEPLOY
+		} else if decoded.inscription.Metadata != nil {
+			parsedOutput.Action = decoded.inscription.Metadata.Action
+		}
+
+		if parsedOutput.Action != "" && parsedOutput.Action != ACTION_TRANSFER {
+			action = parsedOutput.Action
+		}
+
+		if parsedOutput.IsFee {
+			parsed.Fee += parsedOutput.Amount
+		}
+
+		if config.BurnAddress != nil && decoded.address == *config.BurnAddress {
+			parsed.Operation = ACTION_REDEEM
 		}
+
+		parsed.TotalOutput += parsedOutput.Amount
+		parsed.NetAmounts[decoded.address] += int64(parsedOutput.Amount)
+		parsed.Outputs = append(parsed.Outputs, parsedOutput)
 	}
 
-	decoded, err := hex.DecodeString(scriptTokens[6])
-	if err != nil {
-		return false, nil
+	if action != "" {
+		parsed.Operation = action
 	}
 
+	return &parsed, nil
+}
+
+// parseMneeScript decodes the ASM tokens of a MNEE output script, returning
+// false if the script is not valid for the system config.
+func parseMneeScript(scriptTokens []string, config *SystemConfig) (*mneeScript, bool) {
+
+	decoded, address, valid := decodeMneeOutput(scriptTokens, config)
+	if !valid {
+		return nil, false
+	}
+
+	var parsed mneeScript
 	var transferInscription TransferTokenInscription
 
-	err = json.Unmarshal(decoded, &transferInscription)
+	err := json.Unmarshal(decoded, &transferInscription)
+	if err != nil {
+		err = json.Unmarshal(decoded, &parsed.inscription)
+		if err != nil || !validateDeployChainInscription(&parsed.inscription, config) {
+			return nil, false
+		}
+	} else {
+		if !validateTransferInscription(&transferInscription, config) {
+			return nil, false
+		}
+
+		parsed.inscription.BaseTokenInscription = transferInscription.BaseTokenInscription
+		parsed.inscription.TokenID = transferInscription.TokenID
+
+		// Mint and redeem inscriptions are transfers carrying metadata; keep it when present.
+		var metadata DeployChainInscription
+		if json.Unmarshal(decoded, &metadata) == nil {
+			parsed.inscription.Metadata = metadata.Metadata
+		}
+	}
+
+	parsed.amount, err = strconv.ParseUint(parsed.inscription.Amount, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	parsed.address = address
+	return &parsed, true
+}
+
+// parseMneeDeployScript decodes the deploy+mint output that created the token,
+// i.e. the output whose outpoint is SystemConfig.TokenId, returning false for
+// any other output.
+func parseMneeDeployScript(scriptTokens []string, config *SystemConfig, outpoint string) (*mneeScript, bool) {
+
+	if config.TokenId == nil || outpoint != *config.TokenId {
+		return nil, false
+	}
+
+	decoded, address, valid := decodeMneeOutput(scriptTokens, config)
+	if !valid {
+		return nil, false
+	}
+
+	var parsed mneeScript = mneeScript{address: address}
+	err := json.Unmarshal(decoded, &parsed.inscription)
+	if err != nil || parsed.inscription.Protocol != BSV20 || parsed.inscription.Operation != DEPLOY_MINT ||
+		!isPositiveInteger(parsed.inscription.Amount) {
+		return nil, false
+	}
+
+	parsed.amount, err = strconv.ParseUint(parsed.inscription.Amount, 10, 64)
 	if err != nil {
-		var deployInscription DeployChainInscription
+		return nil, false
+	}
+
+	return &parsed, true
+}
+
+// decodeMneeOutput checks the ord envelope and cosigner locking script of a
+// MNEE output and returns its inscription JSON and owner address.
+func decodeMneeOutput(scriptTokens []string, config *SystemConfig) ([]byte, string, bool) {
 
-		err = json.Unmarshal(decoded, &deployInscription)
+	if len(scriptTokens) != 13 && len(scriptTokens) != 15 {
+		return nil, "", false
+	}
+
+	var valid bool = validateOrdInscription(scriptTokens)
+	if !valid {
+		return nil, "", false
+	}
+
+	if len(scriptTokens) == 13 {
+		p2pkhScript, err := script.NewFromASM(strings.Join(scriptTokens[8:], " "))
 		if err != nil {
-			return false, nil
+			return nil, "", false
+		}
+
+		if !p2pkhScript.IsP2PKH() {
+			return nil, "", false
 		}
+	}
 
-		return validateDeployChainInscription(&deployInscription, config), nil
+	valid = validateTransferLockingScript(scriptTokens[8:], config)
+	if !valid {
+		return nil, "", false
+	}
+
+	decoded, err := hex.DecodeString(scriptTokens[6])
+	if err != nil {
+		return nil, "", false
+	}
+
+	publicKeyHash, err := hex.DecodeString(scriptTokens[10])
+	if err != nil {
+		return nil, "", false
+	}
+
+	address, err := script.NewAddressFromPublicKeyHash(publicKeyHash, true)
+	if err != nil {
+		return nil, "", false
 	}
 
-	return validateTransferInscription(&transferInscription, config), nil
+	return decoded, address.AddressString, true
 }
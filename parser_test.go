@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/base64"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -67,3 +69,131 @@ func TestIsMneeScript_Integration(t *testing.T) {
 
 	t.Log("✅ Correctly identified a valid MNEE script")
 }
+
+func TestParseTransaction(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	burn := newTestKey(t)
+	minter := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+		newTestTxo(t, minter.address, 50000, 3),
+	})
+	fixture.config.BurnAddress = &burn.address
+	fixture.config.MintAddress = &minter.address
+
+	t.Log("Test Case 1: A transfer decodes recipient, fee and change...")
+	mneeTransaction, _, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	funding := strings.Repeat("ab", 32)
+	if !assertions.NoError(mneeTransaction.AddInputFrom(funding, 1, "", 1000, nil)) {
+		return
+	}
+
+	parsed, err := fixture.mnee.ParseTransaction(context.Background(), mneeTransaction.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(mneeTransaction.TxID().String(), parsed.Txid)
+	assertions.Equal(ACTION_TRANSFER, parsed.Operation)
+	assertions.Len(parsed.Inputs, 3)
+	assertions.True(parsed.Inputs[0].IsMnee)
+	assertions.Equal(sender.address, parsed.Inputs[0].Address)
+	assertions.Equal(ParsedMneeInput{Vin: 2, Outpoint: funding + "_1"}, parsed.Inputs[2], "Unknown inputs are not MNEE")
+	assertions.Equal([]ParsedMneeOutput{
+		{Vout: 0, Address: recipient.address, Amount: 4000},
+		{Vout: 1, Address: *fixture.config.FeeAddress, Amount: 100, IsFee: true},
+		{Vout: 2, Address: sender.address, Amount: 3900, IsChange: true},
+	}, parsed.Outputs)
+	assertions.Equal(uint64(8000), parsed.TotalInput)
+	assertions.Equal(uint64(8000), parsed.TotalOutput)
+	assertions.Equal(uint64(100), parsed.Fee)
+	assertions.Equal(map[string]int64{
+		sender.address:             -4100,
+		recipient.address:          4000,
+		*fixture.config.FeeAddress: 100,
+	}, parsed.NetAmounts)
+
+	t.Log("Test Case 2: Sending to the burn address is a redeem...")
+	mneeTransaction, _, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(burn.address, 1000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	parsed, err = fixture.mnee.ParseTransaction(context.Background(), mneeTransaction.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(ACTION_REDEEM, parsed.Operation)
+
+	t.Log("Test Case 3: Spending from the mint address is a mint...")
+	mneeTransaction, _, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(minter.wif).
+		AddRecipient(recipient.address, 20000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	parsed, err = fixture.mnee.ParseTransaction(context.Background(), mneeTransaction.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(ACTION_MINT, parsed.Operation)
+
+	t.Log("Test Case 4: The deploy+mint output of the token is a deploy...")
+	fixture = newTestFixture(t, nil)
+	deployAddress, err := script.NewAddressFromString(minter.address)
+	if !assertions.NoError(err) {
+		return
+	}
+	lockingScript, err := lock(deployAddress, fixture.approver.PubKey())
+	if !assertions.NoError(err) {
+		return
+	}
+	deployTransaction := transaction.NewTransaction()
+	if !assertions.NoError(deployTransaction.AddInputFrom(funding, 0, "", 1000, nil)) {
+		return
+	}
+	if !assertions.NoError(deployTransaction.Inscribe(&script.InscriptionArgs{
+		ContentType:   "application/bsv-20",
+		Data:          []byte(`{"p":"bsv-20","op":"deploy+mint","amt":"100000000","dec":"5"}`),
+		LockingScript: lockingScript,
+	})) {
+		return
+	}
+
+	tokenID := deployTransaction.TxID().String() + "_0"
+	fixture.config.TokenId = &tokenID
+	parsed, err = fixture.mnee.ParseTransaction(context.Background(), deployTransaction.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(ACTION_DEPLOY, parsed.Operation)
+	assertions.Equal([]ParsedMneeOutput{
+		{Vout: 0, Address: minter.address, Amount: 100000000, Action: ACTION_DEPLOY},
+	}, parsed.Outputs)
+
+	t.Log("Test Case 5: A deploy+mint output of another token is ignored...")
+	otherTokenID := strings.Repeat("cd", 32) + "_0"
+	fixture = newTestFixture(t, nil)
+	fixture.config.TokenId = &otherTokenID
+	parsed, err = fixture.mnee.ParseTransaction(context.Background(), deployTransaction.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(ACTION_TRANSFER, parsed.Operation)
+	assertions.Empty(parsed.Outputs)
+
+	_, err = fixture.mnee.ParseTransaction(context.Background(), "zz")
+	assertions.Error(err)
+}
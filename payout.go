@@ -0,0 +1,612 @@
+package mnee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// DefaultPayoutBatchSize is the number of recipients per payout transaction
+// when PayoutOptions.BatchSize is not set.
+const DefaultPayoutBatchSize int = 100
+
+// PayoutStatus is the state of a payout batch and of its recipients.
+type PayoutStatus string
+
+const (
+	// PayoutPending batches have not been signed yet.
+	PayoutPending PayoutStatus = "pending"
+	// PayoutSigned batches are signed and persisted but not known to be submitted.
+	PayoutSigned PayoutStatus = "signed"
+	// PayoutSubmitted batches have a ticket that has not reached a terminal status yet.
+	PayoutSubmitted PayoutStatus = "submitted"
+	// PayoutSucceeded batches were accepted by the cosigner.
+	PayoutSucceeded PayoutStatus = "succeeded"
+	// PayoutFailed batches could not be built or were rejected; their recipients were not paid.
+	PayoutFailed PayoutStatus = "failed"
+)
+
+// PayoutOptions configures BatchPayout.
+type PayoutOptions struct {
+	// Signer spends the UTXOs of its addresses and signs the batches. Required.
+	Signer Signer
+	// BatchSize caps the recipients per transaction. Defaults to DefaultPayoutBatchSize.
+	BatchSize int
+	// Concurrency is the number of batches in flight. Above 1, the signer's
+	// UTXOs are first split with a synchronous transfer into one input per
+	// batch so the batches never compete for UTXOs. Defaults to 1.
+	Concurrency int
+	// ChangeAddress receives the change of every batch. By default it goes to
+	// the owner of the last selected UTXO.
+	ChangeAddress string
+	// ManifestPath, if set, is a JSON file where the payout's progress is
+	// persisted after every step. Calling BatchPayout again with the same
+	// recipients and path resumes the payout instead of paying twice.
+	ManifestPath string
+	// Wait configures how each batch's ticket is polled.
+	Wait WaitOptions
+}
+
+// PayoutResult is the outcome of a payout to one recipient.
+type PayoutResult struct {
+	Address  string       `json:"address"`
+	Amount   uint64       `json:"amount"`
+	Batch    int          `json:"batch"`
+	Status   PayoutStatus `json:"status"`
+	TicketID string       `json:"ticketId,omitempty"`
+	Txid     string       `json:"txid,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// PayoutReport lists the result of every recipient, in the order they were given.
+type PayoutReport struct {
+	Results   []PayoutResult `json:"results"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	// Unfinished counts recipients whose batch was still in flight when BatchPayout returned.
+	Unfinished int `json:"unfinished"`
+}
+
+// payoutBatch is one transaction of a payout, as persisted in the manifest.
+type payoutBatch struct {
+	Index      int               `json:"index"`
+	Recipients []TransferMneeDTO `json:"recipients"`
+	// Inputs is the UTXO created for the batch by the pre-split, if any.
+	Inputs   []MneeTxo    `json:"inputs,omitempty"`
+	Status   PayoutStatus `json:"status"`
+	RawTx    string       `json:"rawtx,omitempty"`
+	TicketID string       `json:"ticketId,omitempty"`
+	Txid     string       `json:"txid,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// payoutManifest is the persisted state of a payout.
+type payoutManifest struct {
+	Recipients []TransferMneeDTO `json:"recipients"`
+	Split      bool              `json:"split"`
+	// SplitRawTx is the signed pre-split transaction, persisted before it is submitted.
+	SplitRawTx string `json:"splitRawtx,omitempty"`
+	// SplitSummary describes the outputs of SplitRawTx.
+	SplitSummary *TransferSummary `json:"splitSummary,omitempty"`
+	// SplitBatches are the indexes of the batches funded by SplitRawTx, in output order.
+	SplitBatches []int          `json:"splitBatches,omitempty"`
+	Batches      []*payoutBatch `json:"batches"`
+}
+
+// payoutRun is a BatchPayout in progress.
+type payoutRun struct {
+	mnee     *MNEE
+	options  PayoutOptions
+	mutex    sync.Mutex
+	manifest *payoutManifest
+}
+
+// BatchPayout pays many recipients by splitting them into transactions of at
+// most BatchSize outputs, submitting each through the asynchronous transfer
+// endpoint and waiting for its ticket. A failed batch does not stop the others;
+// its recipients are reported as failed.
+//
+// Each batch, like the pre-split, is signed and persisted to the manifest
+// before it is submitted, so a payout resumed after a crash resubmits the same
+// transaction rather than paying twice. Before a resumed transaction is
+// submitted again, its inputs are checked: one whose cosigned transaction
+// spent them landed, one whose inputs another transaction spent is signed
+// again from the UTXOs that are unspent now, and one whose inputs are spent by
+// a transaction not indexed yet is left unfinished. A batch that cannot be signed because of a cancelled
+// context or an API error stays pending, so a resumed payout signs it again;
+// it only fails when the signer's balance or the recipients make it impossible.
+//
+// The returned error is reserved for failures of the payout as a whole, such as
+// an unreadable manifest, a failed pre-split or a cancelled context; the report
+// is returned with it whenever the payout started.
+func (m *MNEE) BatchPayout(ctx context.Context, recipients []TransferMneeDTO, options PayoutOptions) (_ *PayoutReport, err error) {
+
+	ctx, span := m.startSpan(ctx, "BatchPayout", Attribute{Key: "mnee.recipient_count", Value: len(recipients)})
+	defer func() { span.End(err) }()
+
+	if options.Signer == nil {
+		return nil, ErrNilSigner
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultPayoutBatchSize
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+
+	manifest, err := loadPayoutManifest(options.ManifestPath, recipients, options.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var run payoutRun = payoutRun{mnee: m, options: options, manifest: manifest}
+	err = run.save()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Concurrency > 1 && !manifest.Split {
+		err = run.split(ctx)
+		if err != nil {
+			return run.report(), err
+		}
+	}
+
+	var wait sync.WaitGroup
+	var slots chan struct{} = make(chan struct{}, options.Concurrency)
+	for _, batch := range manifest.Batches {
+		if batch.Status == PayoutSucceeded || batch.Status == PayoutFailed {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wait.Add(1)
+		go func(batch *payoutBatch) {
+			defer wait.Done()
+			defer func() { <-slots }()
+			run.process(ctx, batch)
+		}(batch)
+	}
+	wait.Wait()
+
+	var report *PayoutReport = run.report()
+	if ctx.Err() != nil {
+		return report, ctx.Err()
+	}
+
+	return report, run.save()
+}
+
+// split creates one UTXO per pending batch, holding its amount and fee, with
+// a synchronous transfer to the signer's first address. The split is signed
+// and persisted before it is submitted, and a split persisted by an earlier run
+// is reconciled like a batch: one that landed is not submitted again, and one
+// whose inputs another transaction spent is signed again.
+func (r *payoutRun) split(ctx context.Context) error {
+
+	var landed *transaction.Transaction
+	if r.manifest.SplitRawTx != "" {
+		var err error
+		landed, err = r.reconcileSplit(ctx)
+		if err != nil {
+			return fmt.Errorf("splitting payout utxos: %w", err)
+		}
+	}
+
+	if r.manifest.SplitRawTx == "" {
+		err := r.signSplit(ctx)
+		if err != nil {
+			return fmt.Errorf("splitting payout utxos: %w", err)
+		}
+	}
+
+	if r.manifest.SplitRawTx != "" {
+		err := r.submitSplit(ctx, landed)
+		if err != nil {
+			return fmt.Errorf("splitting payout utxos: %w", err)
+		}
+	}
+
+	r.mutex.Lock()
+	r.manifest.Split = true
+	r.mutex.Unlock()
+
+	return r.save()
+}
+
+// reconcileSplit checks whether the split persisted by an earlier run landed,
+// returning its cosigned transaction if so. A split whose inputs another
+// transaction spent is dropped from the manifest so that it is signed again.
+func (r *payoutRun) reconcileSplit(ctx context.Context) (*transaction.Transaction, error) {
+
+	signed, err := transaction.NewTransactionFromHex(r.manifest.SplitRawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, landed, err := r.mnee.landing(ctx, signed)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case landingLanded:
+		return landed, nil
+	case landingConflict:
+		r.mutex.Lock()
+		r.manifest.SplitRawTx, r.manifest.SplitSummary, r.manifest.SplitBatches = "", nil, nil
+		r.mutex.Unlock()
+
+		return nil, r.save()
+	case landingUnknown:
+		return nil, fmt.Errorf("inputs of %s spent by a transaction not indexed yet", signed.TxID().String())
+	default:
+		return nil, nil
+	}
+}
+
+// signSplit signs the pre-split for the pending batches and persists it. With
+// at most one pending batch there is nothing to split and nothing is signed.
+func (r *payoutRun) signSplit(ctx context.Context) error {
+
+	config, err := r.mnee.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := r.options.Signer.Addresses(ctx)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("%w: signer has no addresses", ErrUnknownSignerAddress)
+	}
+
+	var pending []*payoutBatch
+	var builder *TransferBuilder = r.mnee.NewTransferBuilder().WithSigner(r.options.Signer).WithChangeAddress(r.options.ChangeAddress)
+	for _, batch := range r.manifest.Batches {
+		if batch.Status != PayoutPending || len(batch.Inputs) > 0 {
+			continue
+		}
+
+		var total uint64
+		for _, recipient := range batch.Recipients {
+			total += recipient.Amount
+		}
+		if fee, ok := TieredFeePolicy(config.Fees, total); ok {
+			total += fee.Fee
+		}
+
+		pending = append(pending, batch)
+		builder.AddRecipient(addresses[0], total)
+	}
+
+	if len(pending) <= 1 {
+		return nil
+	}
+
+	splitTransaction, summary, err := builder.sign(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	r.manifest.SplitRawTx = splitTransaction.Hex()
+	r.manifest.SplitSummary = summary
+	r.manifest.SplitBatches = make([]int, 0, len(pending))
+	for _, batch := range pending {
+		r.manifest.SplitBatches = append(r.manifest.SplitBatches, batch.Index)
+	}
+	r.mutex.Unlock()
+
+	return r.save()
+}
+
+// submitSplit submits the persisted pre-split, unless it already landed, and
+// hands its outputs to the batches as inputs.
+func (r *payoutRun) submitSplit(ctx context.Context, landed *transaction.Transaction) error {
+
+	signed, err := transaction.NewTransactionFromHex(r.manifest.SplitRawTx)
+	if err != nil {
+		return err
+	}
+
+	if landed == nil {
+		response, err := r.mnee.submitSync(ctx, signed.Bytes())
+		if err != nil {
+			return err
+		}
+		if response.Txhex == nil {
+			return fmt.Errorf("cosigner returned no transaction")
+		}
+
+		landed, err = transaction.NewTransactionFromHex(*response.Txhex)
+		if err != nil {
+			return err
+		}
+	}
+
+	var splitTxos []MneeTxo = outputTxos(r.manifest.SplitSummary, signed, landed.Hex(), OutputRecipient)
+	if len(splitTxos) != len(r.manifest.SplitBatches) {
+		return fmt.Errorf("expected %d outputs, found %d", len(r.manifest.SplitBatches), len(splitTxos))
+	}
+
+	r.mutex.Lock()
+	for i, index := range r.manifest.SplitBatches {
+		r.manifest.Batches[index].Inputs = []MneeTxo{splitTxos[i]}
+	}
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// process drives a batch from its persisted status to a terminal one.
+// Context errors leave the batch where it is so the payout can be resumed.
+func (r *payoutRun) process(ctx context.Context, batch *payoutBatch) {
+
+	if batch.Status == PayoutSigned && !r.reconcile(ctx, batch) {
+		return
+	}
+
+	r.advance(ctx, batch)
+}
+
+// reconcile checks whether a batch signed by an earlier run already landed,
+// since the cosigned transaction has another txid than the persisted one. A
+// batch whose inputs another transaction spent can never land: it is signed
+// again, without the pre-split UTXO it may have held, from the UTXOs that are
+// unspent now. It reports whether the batch should be advanced.
+func (r *payoutRun) reconcile(ctx context.Context, batch *payoutBatch) bool {
+
+	signed, err := transaction.NewTransactionFromHex(batch.RawTx)
+	if err != nil {
+		r.update(batch, func() { batch.Status, batch.Error = PayoutFailed, err.Error() })
+		return false
+	}
+
+	status, landed, err := r.mnee.landing(ctx, signed)
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+
+	switch status {
+	case landingLanded:
+		r.update(batch, func() { batch.Status, batch.Txid, batch.Error = PayoutSucceeded, landed.TxID().String(), "" })
+		return false
+	case landingConflict:
+		r.update(batch, func() {
+			batch.Status, batch.RawTx, batch.TicketID, batch.Inputs = PayoutPending, "", "", nil
+		})
+		return true
+	case landingUnknown:
+		return false
+	default:
+		return true
+	}
+}
+
+// advance signs, submits and waits for a batch, starting from its current status.
+func (r *payoutRun) advance(ctx context.Context, batch *payoutBatch) {
+
+	if batch.Status == PayoutPending {
+		r.sign(ctx, batch)
+	}
+
+	if batch.Status == PayoutSigned {
+		r.submit(ctx, batch)
+	}
+
+	if batch.Status == PayoutSubmitted {
+		ticket, err := r.mnee.WaitForTicket(ctx, batch.TicketID, r.options.Wait)
+		switch {
+		case errors.Is(err, ErrTicketFailed):
+			r.update(batch, func() { batch.Status, batch.Error = PayoutFailed, err.Error() })
+		case err != nil:
+			return
+		default:
+			r.update(batch, func() {
+				batch.Status = PayoutSucceeded
+				if ticket.TxID != nil {
+					batch.Txid = *ticket.TxID
+				}
+			})
+		}
+	}
+}
+
+// sign builds and signs the batch's transaction, persisting it before
+// submission. A batch the balance or its recipients make impossible fails;
+// other errors, such as a cancelled context or an unavailable API, are recorded
+// and leave it pending.
+func (r *payoutRun) sign(ctx context.Context, batch *payoutBatch) {
+
+	var builder *TransferBuilder = r.mnee.NewTransferBuilder().
+		WithSigner(r.options.Signer).
+		AddRecipients(batch.Recipients...).
+		WithChangeAddress(r.options.ChangeAddress)
+	if len(batch.Inputs) > 0 {
+		builder.WithInputs(batch.Inputs)
+	}
+
+	mneeTransaction, _, err := builder.sign(ctx)
+	if err != nil {
+		if payoutImpossible(err) {
+			r.update(batch, func() { batch.Status, batch.Error = PayoutFailed, err.Error() })
+		} else {
+			r.update(batch, func() { batch.Error = err.Error() })
+		}
+		return
+	}
+
+	r.update(batch, func() { batch.Status, batch.RawTx, batch.Error = PayoutSigned, mneeTransaction.Hex(), "" })
+}
+
+// submit sends a signed batch to the asynchronous endpoint. A rejected batch
+// fails; other errors leave it signed so the payout can be resumed.
+func (r *payoutRun) submit(ctx context.Context, batch *payoutBatch) {
+
+	signed, err := transaction.NewTransactionFromHex(batch.RawTx)
+	if err != nil {
+		r.update(batch, func() { batch.Status, batch.Error = PayoutFailed, err.Error() })
+		return
+	}
+
+	ticketID, err := r.mnee.submitAsync(ctx, signed.Bytes(), nil, nil)
+	if err != nil {
+		if isRejected(err) {
+			r.update(batch, func() { batch.Status, batch.Error = PayoutFailed, err.Error() })
+		}
+		return
+	}
+
+	r.update(batch, func() { batch.Status, batch.TicketID = PayoutSubmitted, *ticketID })
+}
+
+// payoutImpossible reports whether a batch failed to build for a reason that
+// signing it again cannot fix: an insufficient balance or invalid recipients.
+func payoutImpossible(err error) bool {
+	return errors.Is(err, ErrInsufficientMneeBalance) || errors.Is(err, ErrTransferAmountGreaterThan0) ||
+		errors.Is(err, ErrAmountDecimalsMismatch) || errors.Is(err, ErrInvalidAddress) ||
+		errors.Is(err, ErrInvalidPublicKeyHash) || errors.Is(err, ErrUnknownSignerAddress) ||
+		errors.Is(err, ErrInvalidConfig)
+}
+
+// update applies a change to a batch and persists the manifest. Persisting
+// errors are recorded on the batch; they surface again from the final save.
+func (r *payoutRun) update(batch *payoutBatch, change func()) {
+
+	r.mutex.Lock()
+	change()
+	r.mutex.Unlock()
+
+	err := r.save()
+	if err != nil {
+		r.mutex.Lock()
+		batch.Error = err.Error()
+		r.mutex.Unlock()
+	}
+}
+
+// save writes the manifest atomically (write then rename), if a path is set.
+func (r *payoutRun) save() error {
+
+	if r.options.ManifestPath == "" {
+		return nil
+	}
+
+	r.mutex.Lock()
+	encoded, err := json.MarshalIndent(r.manifest, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(r.options.ManifestPath), filepath.Base(r.options.ManifestPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+
+	_, err = temporary.Write(encoded)
+	if err == nil {
+		err = temporary.Sync()
+	}
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporary.Name(), r.options.ManifestPath)
+}
+
+// report lists the results of every recipient from the manifest.
+func (r *payoutRun) report() *PayoutReport {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var report PayoutReport = PayoutReport{Results: make([]PayoutResult, 0, len(r.manifest.Recipients))}
+	for _, batch := range r.manifest.Batches {
+		for _, recipient := range batch.Recipients {
+			report.Results = append(report.Results, PayoutResult{
+				Address:  recipient.Address,
+				Amount:   recipient.Amount,
+				Batch:    batch.Index,
+				Status:   batch.Status,
+				TicketID: batch.TicketID,
+				Txid:     batch.Txid,
+				Error:    batch.Error,
+			})
+
+			switch batch.Status {
+			case PayoutSucceeded:
+				report.Succeeded++
+			case PayoutFailed:
+				report.Failed++
+			default:
+				report.Unfinished++
+			}
+		}
+	}
+
+	return &report
+}
+
+// loadPayoutManifest reads the manifest at path, checking that it belongs to
+// the same recipients, or creates a new one split into batches.
+func loadPayoutManifest(path string, recipients []TransferMneeDTO, batchSize int) (*payoutManifest, error) {
+
+	if path != "" {
+		encoded, err := os.ReadFile(path)
+		if err == nil {
+			var manifest payoutManifest
+			err = json.Unmarshal(encoded, &manifest)
+			if err != nil {
+				return nil, fmt.Errorf("reading payout manifest: %w", err)
+			}
+
+			if !slices.Equal(manifest.Recipients, recipients) {
+				return nil, ErrPayoutManifestMismatch
+			}
+
+			return &manifest, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading payout manifest: %w", err)
+		}
+	}
+
+	var manifest payoutManifest = payoutManifest{Recipients: recipients, Batches: make([]*payoutBatch, 0)}
+	for start := 0; start < len(recipients); start += batchSize {
+		manifest.Batches = append(manifest.Batches, &payoutBatch{
+			Index:      len(manifest.Batches),
+			Recipients: recipients[start:min(start+batchSize, len(recipients))],
+			Status:     PayoutPending,
+		})
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,294 @@
+package mnee_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPayoutRecipients returns `count` recipients of `amount` at fresh addresses.
+func newPayoutRecipients(t *testing.T, count int, amount uint64) []mnee.TransferMneeDTO {
+	recipients := make([]mnee.TransferMneeDTO, 0, count)
+	for range count {
+		privateKey, err := primitives.NewPrivateKey()
+		require.NoError(t, err)
+		address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+		require.NoError(t, err)
+		recipients = append(recipients, mnee.TransferMneeDTO{Address: address.AddressString, Amount: amount})
+	}
+
+	return recipients
+}
+
+// newPayoutFixture starts a fake cosigner and funds a fresh signer with `amount`.
+func newPayoutFixture(t *testing.T, amount uint64) (*mneetest.Server, *mnee.MNEE, mnee.Signer, string) {
+	server := mneetest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := server.Client(mnee.WithRetryPolicy(mnee.RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+
+	privateKey, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+	signer, err := mnee.NewKeySigner(privateKey)
+	require.NoError(t, err)
+	addresses, err := signer.Addresses(context.Background())
+	require.NoError(t, err)
+
+	_, err = server.Fund(addresses[0], amount)
+	require.NoError(t, err)
+
+	return server, client, signer, addresses[0]
+}
+
+func TestBatchPayout_Parallel(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, sender := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 250, 10)
+	options := mnee.PayoutOptions{
+		Signer:       signer,
+		BatchSize:    100,
+		Concurrency:  3,
+		ManifestPath: filepath.Join(t.TempDir(), "payout.json"),
+		Wait:         mnee.WaitOptions{InitialInterval: time.Millisecond},
+	}
+
+	t.Log("Test Case 1: Recipients are paid in bounded, pre-split batches...")
+	report, err := client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(250, report.Succeeded)
+	assertions.Zero(report.Failed)
+	assertions.Len(report.Results, 250)
+	assertions.Equal(recipients[249].Address, report.Results[249].Address)
+	assertions.Equal(2, report.Results[249].Batch)
+	assertions.NotEmpty(report.Results[0].Txid)
+	for _, recipient := range recipients {
+		assertions.Equal(uint64(10), server.Balance(recipient.Address))
+	}
+	assertions.Equal(1, server.Calls(mneetest.EndpointTransfer), "one synchronous pre-split")
+	assertions.Equal(3, server.Calls(mneetest.EndpointTransferAsync))
+	// 10000 - 2500 paid - 3 batch fees of 100 - the split fee of 100.
+	assertions.Equal(uint64(7100), server.Balance(sender))
+
+	t.Log("Test Case 2: Running a finished manifest again pays nobody twice...")
+	report, err = client.BatchPayout(context.Background(), recipients, options)
+	assertions.NoError(err)
+	assertions.Equal(250, report.Succeeded)
+	assertions.Equal(3, server.Calls(mneetest.EndpointTransferAsync))
+
+	t.Log("Test Case 3: A manifest of other recipients is refused...")
+	_, err = client.BatchPayout(context.Background(), recipients[:10], options)
+	assertions.ErrorIs(err, mnee.ErrPayoutManifestMismatch)
+}
+
+func TestBatchPayout_FailuresAndResume(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, _ := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 6, 100)
+	recipients[5].Address = "not-an-address"
+	options := mnee.PayoutOptions{
+		Signer:       signer,
+		BatchSize:    2,
+		ManifestPath: filepath.Join(t.TempDir(), "payout.json"),
+		Wait:         mnee.WaitOptions{InitialInterval: time.Millisecond},
+	}
+
+	t.Log("Test Case 1: Invalid batches fail and unknown submission outcomes stay unfinished...")
+	server.InjectFault(mneetest.EndpointTransferAsync, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	report, err := client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(2, report.Unfinished, "the first batch's submission failed with a 503")
+	assertions.Equal(mnee.PayoutSigned, report.Results[0].Status)
+	assertions.Equal(2, report.Succeeded)
+	assertions.Equal(2, report.Failed)
+	assertions.NotEmpty(report.Results[5].Error)
+	assertions.Zero(server.Balance(recipients[0].Address))
+
+	t.Log("Test Case 2: Resuming resubmits the persisted transaction...")
+	report, err = client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Succeeded)
+	assertions.Equal(2, report.Failed)
+	assertions.Zero(report.Unfinished)
+	for _, recipient := range recipients[:4] {
+		assertions.Equal(uint64(100), server.Balance(recipient.Address))
+	}
+}
+
+func TestBatchPayout_ResumeLanded(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, _ := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 4, 100)
+	options := mnee.PayoutOptions{
+		Signer:       signer,
+		BatchSize:    2,
+		ManifestPath: filepath.Join(t.TempDir(), "payout.json"),
+		Wait:         mnee.WaitOptions{InitialInterval: time.Millisecond},
+	}
+
+	report, err := client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Succeeded)
+	landedTxid := report.Results[0].Txid
+
+	t.Log("Test Case 1: A batch that landed before a crash is recognised by its cosigned transaction...")
+	// Rewind the first batch to the state persisted right before its submission.
+	encoded, err := os.ReadFile(options.ManifestPath)
+	require.NoError(t, err)
+	var manifest map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &manifest))
+	batch := manifest["batches"].([]any)[0].(map[string]any)
+	batch["status"] = string(mnee.PayoutSigned)
+	delete(batch, "ticketId")
+	delete(batch, "txid")
+	encoded, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(options.ManifestPath, encoded, 0o600))
+
+	signed, err := transaction.NewTransactionFromHex(batch["rawtx"].(string))
+	require.NoError(t, err)
+	assertions.NotEqual(landedTxid, signed.TxID().String(), "the cosigner changes the txid")
+
+	submits := server.Calls(mneetest.EndpointTransferAsync)
+	report, err = client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Succeeded)
+	assertions.Equal(landedTxid, report.Results[0].Txid)
+	assertions.Equal(submits, server.Calls(mneetest.EndpointTransferAsync), "the landed batch must not be submitted again")
+	for _, recipient := range recipients {
+		assertions.Equal(uint64(100), server.Balance(recipient.Address))
+	}
+}
+
+func TestBatchPayout_SignErrorsLeavePending(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, _ := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 4, 100)
+	options := mnee.PayoutOptions{
+		Signer:       signer,
+		BatchSize:    2,
+		ManifestPath: filepath.Join(t.TempDir(), "payout.json"),
+		Wait:         mnee.WaitOptions{InitialInterval: time.Millisecond},
+	}
+
+	t.Log("Test Case 1: A batch whose UTXOs could not be fetched stays pending...")
+	server.InjectFault(mneetest.EndpointUtxos, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	report, err := client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(mnee.PayoutPending, report.Results[0].Status)
+	assertions.NotEmpty(report.Results[0].Error)
+	assertions.Equal(2, report.Unfinished)
+	assertions.Equal(2, report.Succeeded)
+	assertions.Zero(report.Failed)
+
+	t.Log("Test Case 2: Resuming signs it again...")
+	report, err = client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Succeeded)
+	assertions.Empty(report.Results[0].Error)
+
+	t.Log("Test Case 3: A cancelled context leaves every batch pending...")
+	options.ManifestPath = filepath.Join(t.TempDir(), "payout.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err = client.BatchPayout(ctx, newPayoutRecipients(t, 2, 100), options)
+	assertions.ErrorIs(err, context.Canceled)
+	if assertions.NotNil(report) {
+		assertions.Equal(2, report.Unfinished)
+		assertions.Zero(report.Failed)
+	}
+}
+
+func TestBatchPayout_ResumeSplit(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, _ := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 4, 100)
+	options := mnee.PayoutOptions{
+		Signer:       signer,
+		BatchSize:    2,
+		Concurrency:  2,
+		ManifestPath: filepath.Join(t.TempDir(), "payout.json"),
+		Wait:         mnee.WaitOptions{InitialInterval: time.Millisecond},
+	}
+
+	readManifest := func() map[string]any {
+		encoded, err := os.ReadFile(options.ManifestPath)
+		require.NoError(t, err)
+		var manifest map[string]any
+		require.NoError(t, json.Unmarshal(encoded, &manifest))
+		return manifest
+	}
+
+	t.Log("Test Case 1: The split is persisted before it is submitted...")
+	server.InjectFault(mneetest.EndpointTransfer, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	_, err := client.BatchPayout(context.Background(), recipients, options)
+	assertions.ErrorIs(err, mnee.ErrServerUnavailable)
+	splitRawTx := readManifest()["splitRawtx"]
+	assertions.NotEmpty(splitRawTx)
+
+	t.Log("Test Case 2: Resuming submits the persisted split...")
+	server.InjectFault(mneetest.EndpointTransferAsync,
+		mneetest.Fault{StatusCode: http.StatusServiceUnavailable}, mneetest.Fault{StatusCode: http.StatusServiceUnavailable})
+	report, err := client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Unfinished, "both batch submissions failed with a 503")
+	assertions.Equal(2, server.Calls(mneetest.EndpointTransfer))
+	manifest := readManifest()
+	assertions.Equal(splitRawTx, manifest["splitRawtx"])
+
+	t.Log("Test Case 3: A split that landed before a crash is not submitted again...")
+	// Rewind to the state persisted right before the split's submission.
+	manifest["split"] = false
+	for _, batch := range manifest["batches"].([]any) {
+		batch := batch.(map[string]any)
+		batch["status"] = string(mnee.PayoutPending)
+		delete(batch, "rawtx")
+		delete(batch, "inputs")
+	}
+	encoded, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(options.ManifestPath, encoded, 0o600))
+
+	report, err = client.BatchPayout(context.Background(), recipients, options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(4, report.Succeeded)
+	assertions.Equal(2, server.Calls(mneetest.EndpointTransfer), "the landed split must not be submitted again")
+	for _, recipient := range recipients {
+		assertions.Equal(uint64(100), server.Balance(recipient.Address))
+	}
+}
@@ -3,70 +3,154 @@ package mnee
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 )
 
+// WaitOptions configures WaitForTicket. Zero fields take the defaults of DefaultWaitOptions.
+type WaitOptions struct {
+	// InitialInterval is the delay before the second poll.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between polls.
+	MaxInterval time.Duration
+	// Multiplier grows the delay after every poll.
+	Multiplier float64
+	// TerminalStatuses end the wait. FAILED and a ticket with non-empty Errors
+	// are always terminal, whether listed or not.
+	TerminalStatuses []TicketStatus
+	// OnStatus, if set, is called whenever the ticket reaches a new status.
+	OnStatus func(ticket *Ticket)
+}
+
+// DefaultWaitOptions returns the options used for zero WaitOptions fields:
+// polling from 1s up to 10s (x1.5) until SUCCESS, MINED or FAILED.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		InitialInterval:  time.Second,
+		MaxInterval:      10 * time.Second,
+		Multiplier:       1.5,
+		TerminalStatuses: []TicketStatus{SUCCESS, MINED, FAILED},
+	}
+}
+
 // PollTicket polls the MNEE API for the status of an asynchronous transfer ticket.
 // It will continue to poll at the specified `pollingInterval` until the context
 // is canceled or the ticket status is no longer "record not found".
-// It returns the final Ticket details.
-func (m *MNEE) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*Ticket, error) {
+// It returns the final Ticket details. Use WaitForTicket to wait for a terminal status.
+func (m *MNEE) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (_ *Ticket, err error) {
+
+	ctx, span := m.startSpan(ctx, "PollTicket", Attribute{Key: "mnee.ticket_id", Value: ticketID})
+	defer func() { span.End(err) }()
 
 	for {
-		ticketRequest, err := http.NewRequestWithContext(
-			ctx,
-			http.MethodGet,
-			(m.mneeURL + "/v2/ticket?auth_token=" + m.mneeToken + "&ticketID=" + ticketID),
-			nil,
-		)
-		if err != nil {
-			return nil, err
+		ticket, err := m.getTicket(ctx, ticketID)
+		if isRecordNotFound(err) {
+			select {
+			case <-time.After(pollingInterval):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
 		}
 
-		ticketResponse, err := m.httpClient.Do(ticketRequest)
 		if err != nil {
 			return nil, err
 		}
 
-		defer ticketResponse.Body.Close()
+		return ticket, nil
+	}
+}
+
+// WaitForTicket polls an asynchronous transfer ticket with backoff until it
+// reaches one of the terminal statuses, FAILED or reports errors. A ticket that
+// is not found yet is polled again. When the ticket ends FAILED or with errors
+// it is returned together with ErrTicketFailed.
+func (m *MNEE) WaitForTicket(ctx context.Context, ticketID string, options WaitOptions) (_ *Ticket, err error) {
+
+	ctx, span := m.startSpan(ctx, "WaitForTicket", Attribute{Key: "mnee.ticket_id", Value: ticketID})
+	defer func() { span.End(err) }()
+
+	var defaults WaitOptions = DefaultWaitOptions()
+	if options.InitialInterval <= 0 {
+		options.InitialInterval = defaults.InitialInterval
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = defaults.MaxInterval
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = defaults.Multiplier
+	}
+	if len(options.TerminalStatuses) == 0 {
+		options.TerminalStatuses = defaults.TerminalStatuses
+	}
 
-		if ticketResponse.StatusCode == http.StatusForbidden {
-			return nil, ErrForbidden
+	var interval time.Duration = options.InitialInterval
+	var lastStatus TicketStatus
+
+	for {
+		ticket, err := m.getTicket(ctx, ticketID)
+		if err != nil && !isRecordNotFound(err) {
+			return nil, err
 		}
 
-		if ticketResponse.StatusCode != http.StatusOK {
-			var errorResponse map[string]any
-			err = json.NewDecoder(ticketResponse.Body).Decode(&errorResponse)
-			if err != nil {
-				return nil, err
+		if ticket != nil {
+			if ticket.Status != lastStatus && options.OnStatus != nil {
+				options.OnStatus(ticket)
+			}
+			lastStatus = ticket.Status
+
+			if len(ticket.Errors) > 0 {
+				return ticket, fmt.Errorf("%w: %s", ErrTicketFailed, strings.Join(ticket.Errors, "; "))
 			}
 
-			errorMessage, ok := errorResponse["message"].(string)
-			if !ok {
-				return nil, fmt.Errorf("status received from mnee-cosigner -> %d", ticketResponse.StatusCode)
+			if ticket.Status == FAILED {
+				return ticket, ErrTicketFailed
 			}
 
-			if errorMessage == "record not found" {
-				select {
-				case <-time.After(pollingInterval):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				}
-			} else {
-				return nil, errors.New(errorMessage)
+			if slices.Contains(options.TerminalStatuses, ticket.Status) {
+				return ticket, nil
 			}
 		}
 
-		var ticket Ticket
-		err = json.NewDecoder(ticketResponse.Body).Decode(&ticket)
-		if err != nil {
-			return nil, err
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 
-		return &ticket, nil
+		interval = min(time.Duration(float64(interval)*options.Multiplier), options.MaxInterval)
 	}
 }
+
+// getTicket fetches the current state of a ticket.
+func (m *MNEE) getTicket(ctx context.Context, ticketID string) (*Ticket, error) {
+
+	err := ValidateTicketID(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodGet,
+		endpoint:   "/v2/ticket",
+		query:      url.Values{"ticketID": {ticketID}},
+		idempotent: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer ticketResponse.Body.Close()
+
+	var ticket Ticket
+	err = json.NewDecoder(ticketResponse.Body).Decode(&ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket, nil
+}
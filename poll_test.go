@@ -2,7 +2,11 @@ package mnee
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -76,3 +80,74 @@ func TestPollTicket_Integration(t *testing.T) {
 		t.Logf("✅ Successfully polled ticket. Status: %s", ticket.Status)
 	}
 }
+
+func TestWaitForTicket(t *testing.T) {
+	assertions := assert.New(t)
+
+	newTicketServer := func(t *testing.T, tickets ...*Ticket) *MNEE {
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			poll := int(polls.Add(1)) - 1
+			ticket := tickets[min(poll, len(tickets)-1)]
+			if ticket == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"record not found"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(ticket)
+		}))
+		t.Cleanup(server.Close)
+
+		m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+
+	options := WaitOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+
+	t.Log("Test Case 1: Waits past missing and broadcasting tickets until SUCCESS...")
+	m := newTicketServer(t, nil, &Ticket{Status: BROADCASTING}, &Ticket{Status: BROADCASTING}, &Ticket{Status: SUCCESS})
+	var statuses []TicketStatus
+	options.OnStatus = func(ticket *Ticket) {
+		statuses = append(statuses, ticket.Status)
+	}
+	ticket, err := m.WaitForTicket(context.Background(), "ticket", options)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(SUCCESS, ticket.Status)
+	assertions.Equal([]TicketStatus{BROADCASTING, SUCCESS}, statuses, "OnStatus fires once per status")
+
+	t.Log("Test Case 2: Custom terminal statuses...")
+	options.OnStatus = nil
+	options.TerminalStatuses = []TicketStatus{MINED}
+	m = newTicketServer(t, &Ticket{Status: SUCCESS}, &Ticket{Status: MINED})
+	ticket, err = m.WaitForTicket(context.Background(), "ticket", options)
+	assertions.NoError(err)
+	assertions.Equal(MINED, ticket.Status)
+
+	t.Log("Test Case 3: FAILED and errors end the wait with ErrTicketFailed...")
+	m = newTicketServer(t, &Ticket{Status: BROADCASTING, Errors: []string{"double spend"}})
+	ticket, err = m.WaitForTicket(context.Background(), "ticket", options)
+	assertions.ErrorIs(err, ErrTicketFailed)
+	assertions.ErrorContains(err, "double spend")
+	assertions.NotNil(ticket)
+
+	m = newTicketServer(t, &Ticket{Status: FAILED})
+	_, err = m.WaitForTicket(context.Background(), "ticket", WaitOptions{})
+	assertions.ErrorIs(err, ErrTicketFailed)
+
+	m = newTicketServer(t, &Ticket{Status: FAILED})
+	ticket, err = m.WaitForTicket(context.Background(), "ticket", options)
+	assertions.ErrorIs(err, ErrTicketFailed, "FAILED is terminal even when not listed")
+	assertions.Equal(FAILED, ticket.Status)
+
+	t.Log("Test Case 4: Context cancellation stops the wait...")
+	m = newTicketServer(t, &Ticket{Status: BROADCASTING})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = m.WaitForTicket(ctx, "ticket", options)
+	assertions.ErrorIs(err, context.DeadlineExceeded)
+}
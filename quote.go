@@ -0,0 +1,80 @@
+package mnee
+
+import (
+	"context"
+	"slices"
+)
+
+// TransferQuote is the dry-run result of a transfer: the fee tier, the UTXOs
+// that would be consumed and every output that would be created.
+type TransferQuote struct {
+	TransferSummary
+	// TotalDebited is the amount the selected inputs give up beyond the change:
+	// the transfer amount plus the fee, as built by TransferBuilder. For a
+	// self-transfer it includes the amounts sent back to the input addresses.
+	TotalDebited uint64 `json:"totalDebited"`
+	// SelfTransfer is true when the transfer has recipients and every one of
+	// them is an input address, which makes the amounts sent fee-exempt: they
+	// do not count toward the fee tier. The cosigner still requires the fee
+	// output of the tier of a zero amount (or of the change, when it goes to
+	// another address), which Fee reports.
+	SelfTransfer bool `json:"selfTransfer"`
+}
+
+// QuoteTransfer runs the transfer pipeline for UTXOs owned by `addresses`
+// without signing or submitting anything, so no private keys are required.
+// Use it to show the fee, inputs and change before asking the user to confirm.
+func (m *MNEE) QuoteTransfer(ctx context.Context, addresses []string, mneeTransferDTO []TransferMneeDTO) (_ *TransferQuote, err error) {
+
+	ctx, span := m.startSpan(ctx, "QuoteTransfer", Attribute{Key: "mnee.address_count", Value: len(addresses)})
+	defer func() { span.End(err) }()
+
+	_, summary, err := m.NewTransferBuilder().
+		WithAddresses(addresses...).
+		AddRecipients(mneeTransferDTO...).
+		Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inputAddresses []string = make([]string, 0, len(summary.Inputs))
+	for _, txo := range summary.Inputs {
+		inputAddresses = append(inputAddresses, txo.Owners[0])
+	}
+
+	var selfTransfer bool = len(mneeTransferDTO) > 0
+	for _, dto := range mneeTransferDTO {
+		if !slices.Contains(inputAddresses, dto.Address) {
+			selfTransfer = false
+			break
+		}
+	}
+
+	return &TransferQuote{
+		TransferSummary: *summary,
+		TotalDebited:    summary.TotalInput - summary.Change,
+		SelfTransfer:    selfTransfer,
+	}, nil
+}
+
+// MaxSendable returns the largest amount the UTXOs of `addresses` can send to
+// `recipient`: the amount that, plus the fee tier it falls into, uses up the
+// balance. Sending to one of the addresses only pays the fee tier of a zero
+// amount. Pass the result to AddRecipient, or use TransferBuilder.SendAll to
+// solve it while building. It returns ErrInsufficientMneeBalance if the balance
+// does not cover any fee.
+func (m *MNEE) MaxSendable(ctx context.Context, addresses []string, recipient string) (_ uint64, err error) {
+
+	ctx, span := m.startSpan(ctx, "MaxSendable", Attribute{Key: "mnee.address_count", Value: len(addresses)})
+	defer func() { span.End(err) }()
+
+	_, summary, err := m.NewTransferBuilder().
+		WithAddresses(addresses...).
+		SendAll(recipient).
+		Build(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return summary.TotalTransfer, nil
+}
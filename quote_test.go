@@ -0,0 +1,124 @@
+package mnee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteTransfer(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+	})
+
+	t.Log("Test Case 1: Quote a transfer to a third party using only addresses...")
+	quote, err := fixture.mnee.QuoteTransfer(context.Background(), []string{sender.address},
+		[]TransferMneeDTO{{Address: recipient.address, Amount: 4000}})
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(100), quote.Fee)
+	assertions.Equal(fixture.config.Fees[0], *quote.FeeTier)
+	assertions.Len(quote.Inputs, 2)
+	assertions.Equal(uint64(3900), quote.Change)
+	assertions.Equal(uint64(4100), quote.TotalDebited)
+	assertions.False(quote.SelfTransfer)
+
+	t.Log("Test Case 2: Quote a transfer back to the sender...")
+	quote, err = fixture.mnee.QuoteTransfer(context.Background(), []string{sender.address},
+		[]TransferMneeDTO{{Address: sender.address, Amount: 1000}})
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.True(quote.SelfTransfer)
+	assertions.Len(quote.Inputs, 1)
+	assertions.Equal(uint64(100), quote.Fee, "Self-transfers pay the fee tier of a zero amount")
+	assertions.Equal(fixture.config.Fees[0], *quote.FeeTier)
+	assertions.Equal(uint64(1100), quote.TotalDebited)
+	assertions.Equal(uint64(1900), quote.Change)
+
+	_, summary, err := fixture.mnee.NewTransferBuilder().
+		WithAddresses(sender.address).
+		AddRecipient(sender.address, 1000).
+		Build(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal(summary.Fee, quote.Fee)
+		assertions.Equal(summary.TotalInput-summary.Change, quote.TotalDebited)
+	}
+
+	t.Log("Test Case 3: A quote without recipients is not a self-transfer...")
+	quote, err = fixture.mnee.QuoteTransfer(context.Background(), []string{sender.address}, nil)
+	if assertions.NoError(err) {
+		assertions.False(quote.SelfTransfer)
+		assertions.Zero(quote.TotalTransfer)
+	}
+
+	t.Log("Test Case 4: Quote more than the balance...")
+	_, err = fixture.mnee.QuoteTransfer(context.Background(), []string{sender.address},
+		[]TransferMneeDTO{{Address: recipient.address, Amount: 9000}})
+	assertions.ErrorIs(err, ErrInsufficientMneeBalance)
+}
+
+func TestMaxSendable(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	other := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+		newTestTxo(t, other.address, 1000500, 3),
+		newTestTxo(t, other.address, 1500, 4),
+	})
+
+	t.Log("Test Case 1: The balance minus the fee tier of the amount...")
+	amount, err := fixture.mnee.MaxSendable(context.Background(), []string{sender.address}, recipient.address)
+	assertions.NoError(err)
+	assertions.Equal(uint64(7900), amount)
+
+	t.Log("Test Case 2: Amounts across a tier boundary use the higher tier when it sends more...")
+	amount, err = fixture.mnee.MaxSendable(context.Background(), []string{other.address}, recipient.address)
+	assertions.NoError(err)
+	assertions.Equal(uint64(1001000), amount, "1,002,000 pays the 1,000 tier")
+
+	_, summary, err := fixture.mnee.NewTransferBuilder().
+		WithAddresses(sender.address, other.address).
+		SendAll(recipient.address).
+		Build(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal(uint64(1009000), summary.TotalTransfer)
+		assertions.Equal(uint64(1000), summary.Fee)
+		assertions.Zero(summary.Change)
+		assertions.Len(summary.Inputs, 4)
+		assertions.Equal("sweep", summary.CoinSelector)
+	}
+
+	t.Log("Test Case 3: SendAll after fixed recipients, and to the sender itself...")
+	_, summary, err = fixture.mnee.NewTransferBuilder().
+		WithAddresses(sender.address).
+		AddRecipient(other.address, 1000).
+		SendAll(recipient.address).
+		Build(context.Background())
+	if assertions.NoError(err) {
+		assertions.Equal([]TransferOutput{
+			{Kind: OutputRecipient, Address: other.address, Amount: 1000},
+			{Kind: OutputRecipient, Address: recipient.address, Amount: 6900},
+			{Kind: OutputFee, Address: *fixture.config.FeeAddress, Amount: 100},
+		}, summary.Outputs)
+	}
+
+	amount, err = fixture.mnee.MaxSendable(context.Background(), []string{sender.address}, sender.address)
+	assertions.NoError(err)
+	assertions.Equal(uint64(7900), amount, "self transfers pay the zero amount tier")
+
+	t.Log("Test Case 4: Balances that do not cover a fee are rejected...")
+	_, err = fixture.mnee.MaxSendable(context.Background(), []string{recipient.address}, sender.address)
+	assertions.ErrorIs(err, ErrInsufficientMneeBalance)
+}
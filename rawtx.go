@@ -1,7 +1,6 @@
 package mnee
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
@@ -21,53 +20,66 @@ import (
 // (e.g., one created by PartialSign) and will be submitted directly to the
 // cosigner. The function waits for the cosigner's response and returns
 // the final, fully-signed transaction details.
-func (m *MNEE) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*TransferResponseDTO, error) {
+func (m *MNEE) SubmitRawTxSync(ctx context.Context, rawTxHex string) (_ *TransferResponseDTO, err error) {
+
+	ctx, span := m.startSpan(ctx, "SubmitRawTxSync")
+	defer func() { span.End(err) }()
 
 	txBytes, err := hex.DecodeString(rawTxHex)
 	if err != nil {
 		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
 	}
 
-	base64EncodedTx := base64.StdEncoding.EncodeToString(txBytes)
+	return m.submitSync(ctx, txBytes)
+}
 
-	jsonBody, err := json.Marshal(map[string]string{"rawtx": base64EncodedTx})
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sync request: %w", err)
-	}
+// SubmitRawTxAsync submits a pre-built, partially signed transaction hex
+// (as a string) to the MNEE asynchronous transfer endpoint.
+//
+// This is an "expert" function. The rawTxHex must be a valid MNEE transaction
+// (e.g., one created by PartialSign). It submits the transaction and
+// immediately returns a ticketID for polling.
+func (m *MNEE) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (_ *string, err error) {
+
+	ctx, span := m.startSpan(ctx, "SubmitRawTxAsync")
+	defer func() { span.End(err) }()
 
-	transferRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		(m.mneeURL + "/v1/transfer?auth_token=" + m.mneeToken),
-		bytes.NewBuffer(jsonBody),
-	)
+	txBytes, err := hex.DecodeString(rawTxHex)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
 	}
 
-	transferResponse, err := m.httpClient.Do(transferRequest)
+	return m.submitAsync(ctx, txBytes, callbackURL, callbackSecret)
+}
+
+// postSync posts a signed transaction to the synchronous transfer endpoint
+// and decodes the cosigned transaction returned by the API.
+func (m *MNEE) postSync(ctx context.Context, txBytes []byte) (*TransferResponseDTO, error) {
+
+	jsonBody, err := json.Marshal(map[string]string{"rawtx": base64.StdEncoding.EncodeToString(txBytes)})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to marshal sync request: %w", err)
 	}
-	defer transferResponse.Body.Close()
 
-	if transferResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
+	var landed *transaction.Transaction
+
+	transferResponse, err := m.do(ctx, m.submitRequest("/v1/transfer", jsonBody, txBytes, &landed))
+	if errors.Is(err, ErrTransferAlreadySubmitted) && landed != nil {
+		var txHex string = landed.Hex()
+		var txID string = landed.TxID().String()
+
+		return &TransferResponseDTO{
+			Txid:  &txID,
+			Txhex: &txHex,
+		}, nil
 	}
 
-	if transferResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(transferResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", transferResponse.StatusCode)
-		}
-		return nil, errors.New(errorMessage)
+	if err != nil {
+		return nil, err
 	}
 
+	defer transferResponse.Body.Close()
+
 	var transferResponseBody struct {
 		Rawtx *string `json:"rawtx,omitempty"`
 	}
@@ -82,12 +94,15 @@ func (m *MNEE) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*TransferR
 		if err != nil {
 			return nil, err
 		}
+
 		finalTx, err := transaction.NewTransactionFromBytes(transactionBytes)
 		if err != nil {
 			return nil, err
 		}
+
 		var txHex string = finalTx.Hex()
 		var txID string = finalTx.TxID().String()
+
 		return &TransferResponseDTO{
 			Txid:  &txID,
 			Txhex: &txHex,
@@ -100,23 +115,12 @@ func (m *MNEE) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*TransferR
 	}, nil
 }
 
-// SubmitRawTxAsync submits a pre-built, partially signed transaction hex
-// (as a string) to the MNEE asynchronous transfer endpoint.
-//
-// This is an "expert" function. The rawTxHex must be a valid MNEE transaction
-// (e.g., one created by PartialSign). It submits the transaction and
-// immediately returns a ticketID for polling.
-func (m *MNEE) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (*string, error) {
-
-	txBytes, err := hex.DecodeString(rawTxHex)
-	if err != nil {
-		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
-	}
-
-	base64EncodedTx := base64.StdEncoding.EncodeToString(txBytes)
+// postAsync posts a signed transaction to the asynchronous transfer endpoint
+// and returns the ticket ID issued by the API.
+func (m *MNEE) postAsync(ctx context.Context, txBytes []byte, callbackURL *string, callbackSecret *string) (*string, error) {
 
 	var transferRequestDTO TransferRequestDTO = TransferRequestDTO{
-		RawTx:          base64EncodedTx,
+		RawTx:          base64.StdEncoding.EncodeToString(txBytes),
 		CallbackURL:    callbackURL,
 		CallbackSecret: callbackSecret,
 	}
@@ -126,47 +130,61 @@ func (m *MNEE) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackUR
 		return nil, err
 	}
 
-	transferRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		(m.mneeURL + "/v2/transfer?auth_token=" + m.mneeToken),
-		bytes.NewBuffer(transferRequestBuffer),
-	)
+	transferResponse, err := m.do(ctx, m.submitRequest("/v2/transfer", transferRequestBuffer, txBytes, nil))
 	if err != nil {
 		return nil, err
 	}
 
-	transferResponse, err := m.httpClient.Do(transferRequest)
-	if err != nil {
-		return nil, err
-	}
 	defer transferResponse.Body.Close()
 
-	if transferResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if transferResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(transferResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", transferResponse.StatusCode)
-		}
-		return nil, errors.New(errorMessage)
-	}
-
 	bodyBytes, err := io.ReadAll(transferResponse.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+
 	if len(bodyBytes) == 0 {
 		return nil, ErrReceivedEmptyTicketID
 	}
+
 	var ticketID string = string(bodyBytes)
 
 	return &ticketID, nil
 }
+
+// submitRequest describes a transfer submission. With RetryPolicy.SafeSubmit enabled
+// the request is retried, keyed on the submitted txid: before every retry the
+// inputs are checked and, if the cosigned transaction already spent them, retrying
+// stops with ErrTransferAlreadySubmitted (and the landed transaction is stored in
+// landed when non-nil). Retrying also stops with ErrInputsSpent when another
+// transaction spent an input, or with the error of the lookup itself.
+func (m *MNEE) submitRequest(endpoint string, body []byte, txBytes []byte, landed **transaction.Transaction) *apiRequest {
+
+	var call apiRequest = apiRequest{
+		method:   http.MethodPost,
+		endpoint: endpoint,
+		body:     body,
+		headers:  map[string]string{"Content-Type": "application/json"},
+	}
+
+	if !m.retryPolicy.SafeSubmit {
+		return &call
+	}
+
+	submitted, err := transaction.NewTransactionFromBytes(txBytes)
+	if err != nil {
+		return &call
+	}
+
+	call.idempotent = true
+	call.headers["Idempotency-Key"] = submitted.TxID().String()
+	call.beforeRetry = func(ctx context.Context) error {
+		landedTx, err := m.landedOrRetry(ctx, submitted)
+		if landedTx != nil && landed != nil {
+			*landed = landedTx
+		}
+
+		return err
+	}
+
+	return &call
+}
@@ -0,0 +1,149 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiRequest describes a single call to the MNEE API.
+type apiRequest struct {
+	method string
+	// endpoint is the API path without parameters, reported in errors.
+	endpoint string
+	// pathParams are escaped and appended to the endpoint as path segments.
+	pathParams []string
+	// query is encoded into the query string.
+	query   url.Values
+	body    []byte
+	headers map[string]string
+	// idempotent marks calls that may be retried under the retry policy.
+	idempotent bool
+	// beforeRetry runs before every retry; returning an error stops retrying.
+	beforeRetry func(ctx context.Context) error
+}
+
+// requestURI returns the escaped path and query string of the request, to be
+// appended to the base URL. Every endpoint is built here.
+func (call *apiRequest) requestURI() string {
+
+	var builder strings.Builder
+	builder.WriteString(call.endpoint)
+	for _, param := range call.pathParams {
+		builder.WriteString("/")
+		builder.WriteString(url.PathEscape(param))
+	}
+
+	if len(call.query) > 0 {
+		builder.WriteString("?")
+		builder.WriteString(call.query.Encode())
+	}
+
+	return builder.String()
+}
+
+// do executes the request, retrying it according to the client's retry policy.
+// Non-200 responses are returned as *APIError; on success the caller must close
+// the response body.
+func (m *MNEE) do(ctx context.Context, call *apiRequest) (*http.Response, error) {
+
+	for attempt := 1; ; attempt++ {
+		var body io.Reader
+		if call.body != nil {
+			body = bytes.NewReader(call.body)
+		}
+
+		request, err := m.newRequest(ctx, call.method, call.requestURI(), body)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range call.headers {
+			request.Header.Set(key, value)
+		}
+
+		var start time.Time = time.Now()
+		response, err := m.httpClient.Do(request)
+		err = redactedURLError(err)
+		var duration time.Duration = time.Since(start)
+		var attrs []slog.Attr = []slog.Attr{
+			slog.String("method", call.method),
+			slog.String("endpoint", call.endpoint),
+			slog.Int("attempt", attempt),
+			slog.Duration("duration", duration),
+			slog.Int("request_bytes", len(call.body)),
+		}
+
+		var metric RequestMetric = RequestMetric{Method: call.method, Endpoint: call.endpoint, Attempt: attempt, Duration: duration}
+		if response != nil {
+			metric.Status = response.StatusCode
+		}
+
+		if err == nil && response.StatusCode == http.StatusOK {
+			m.instrumentation.RecordRequest(ctx, metric)
+			m.logger.LogAttrs(ctx, slog.LevelDebug, "mnee request",
+				append(attrs, slog.Int("status", response.StatusCode), slog.Int64("response_bytes", response.ContentLength))...)
+			return response, nil
+		}
+
+		if err == nil {
+			err = newAPIError(response, call.endpoint)
+			response.Body.Close()
+		}
+
+		metric.Err = err
+		m.instrumentation.RecordRequest(ctx, metric)
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+		}
+		m.logger.LogAttrs(ctx, slog.LevelWarn, "mnee request failed", append(attrs, slog.String("error", err.Error()))...)
+
+		if !call.idempotent || attempt >= m.retryPolicy.MaxAttempts || !m.retryPolicy.retryable(err) {
+			return nil, err
+		}
+
+		var backoff time.Duration = m.retryPolicy.backoff(attempt, err)
+		m.logger.LogAttrs(ctx, slog.LevelInfo, "mnee request retry",
+			slog.String("method", call.method), slog.String("endpoint", call.endpoint),
+			slog.Int("attempt", attempt+1), slog.Duration("backoff", backoff))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if call.beforeRetry != nil {
+			if retryErr := call.beforeRetry(ctx); retryErr != nil {
+				return nil, retryErr
+			}
+		}
+	}
+}
+
+// newRequest builds an HTTP request against the configured base URL and
+// applies the headers shared by every MNEE API call.
+func (m *MNEE) newRequest(ctx context.Context, method string, endpoint string, body io.Reader) (*http.Request, error) {
+
+	request, err := http.NewRequestWithContext(ctx, method, (m.mneeURL + endpoint), body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("User-Agent", m.userAgent)
+
+	err = m.authenticator.Authenticate(request, *m.mneeToken.Load())
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
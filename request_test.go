@@ -0,0 +1,102 @@
+package mnee_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logEntries decodes the JSON lines written by a slog.JSONHandler.
+func logEntries(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	entries := make([]map[string]any, 0)
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		var entry map[string]any
+		require.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// findEntry returns the first entry with the message, or nil.
+func findEntry(entries []map[string]any, message string) map[string]any {
+	for _, entry := range entries {
+		if entry["msg"] == message {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+func TestWithLogger(t *testing.T) {
+	assertions := assert.New(t)
+	ctx := context.Background()
+
+	server, _, signer, sender := newPayoutFixture(t, 10000)
+	recipients := newPayoutRecipients(t, 1, 100)
+
+	var buffer, all bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(io.MultiWriter(&buffer, &all), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := server.Client(mnee.WithLogger(logger), mnee.WithRetryPolicy(mnee.RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+
+	t.Log("Test Case 1: Requests and transfer stages are logged...")
+	_, err = client.NewTransferBuilder().WithSigner(signer).AddRecipients(recipients...).SubmitSync(ctx)
+	require.NoError(t, err)
+
+	entries := logEntries(t, &buffer)
+	request := findEntry(entries, "mnee request")
+	if assertions.NotNil(request) {
+		assertions.Equal("DEBUG", request["level"])
+		assertions.Equal("/v1/config", request["endpoint"])
+		assertions.Equal(float64(http.StatusOK), request["status"])
+		assertions.Contains(request, "duration")
+		assertions.Contains(request, "response_bytes")
+	}
+	for _, message := range []string{"mnee transfer inputs selected", "mnee transfer fee tier", "mnee transfer signing"} {
+		assertions.NotNil(findEntry(entries, message), message)
+	}
+	if submitted := findEntry(entries, "mnee transfer submitted"); assertions.NotNil(submitted) {
+		assertions.Equal("INFO", submitted["level"])
+		assertions.Equal("sync", submitted["mode"])
+		assertions.NotEmpty(submitted["cosigned_txid"])
+	}
+	if fee := findEntry(entries, "mnee transfer fee tier"); assertions.NotNil(fee) {
+		assertions.Equal(float64(100), fee["fee"])
+	}
+
+	t.Log("Test Case 2: Failed requests are logged with their status...")
+	server.InjectFault(mneetest.EndpointBalance, mneetest.Fault{StatusCode: http.StatusBadRequest})
+	_, err = client.GetBalances(ctx, []string{sender})
+	assertions.Error(err)
+	failed := findEntry(logEntries(t, &buffer), "mnee request failed")
+	if assertions.NotNil(failed) {
+		assertions.Equal("WARN", failed["level"])
+		assertions.Equal(float64(http.StatusBadRequest), failed["status"])
+	}
+
+	t.Log("Test Case 3: The auth token never reaches the log...")
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	offline, err := mnee.NewMneeInstance(mnee.EnvCustom, "secret-token", mnee.WithBaseURL(closed.URL),
+		mnee.WithLogger(logger), mnee.WithRetryPolicy(mnee.RetryPolicy{MaxAttempts: 1}))
+	require.NoError(t, err)
+	_, err = offline.GetBalances(ctx, []string{sender})
+	assertions.Error(err)
+	output := all.String()
+	assertions.Contains(output, "mnee request failed")
+	assertions.NotContains(output, "secret-token")
+	assertions.NotContains(output, mneetest.DefaultToken)
+}
@@ -0,0 +1,257 @@
+package mnee
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+)
+
+// DefaultReservationTTL is how long a reservation or spent marker is kept when
+// ReservationOptions.TTL is not set.
+const DefaultReservationTTL time.Duration = 2 * time.Minute
+
+// maxReservationAttempts bounds how often a transfer re-selects its inputs
+// after losing them to a concurrent transfer.
+const maxReservationAttempts int = 5
+
+// ReservationOptions configures the UTXO reservation layer enabled by WithUTXOReservation.
+type ReservationOptions struct {
+	// TTL bounds how long UTXOs stay reserved by a transfer whose outcome is
+	// unknown (e.g. a timed out submission) and how long spent UTXOs and
+	// chained change are remembered while the API catches up. Defaults to DefaultReservationTTL.
+	TTL time.Duration
+	// ChainChange makes the change outputs of a transfer submitted with
+	// SubmitSync spendable by the next transfer before the API indexes them.
+	ChainChange bool
+}
+
+// WithUTXOReservation lets concurrent transfers of the same client share a
+// wallet. UTXOs selected by an in-flight SubmitSync or SubmitAsync (and the
+// transfer functions built on them) are reserved and skipped by every other
+// transfer. They are released if the cosigner rejects the transfer and marked
+// spent once it accepts it.
+func WithUTXOReservation(opts ReservationOptions) Option {
+	return func(m *MNEE) error {
+		if opts.TTL <= 0 {
+			opts.TTL = DefaultReservationTTL
+		}
+
+		m.reservations = &utxoReservations{
+			ttl:         opts.TTL,
+			chainChange: opts.ChainChange,
+			reserved:    make(map[string]time.Time),
+			spent:       make(map[string]time.Time),
+			change:      make(map[string]pendingChange),
+		}
+
+		return nil
+	}
+}
+
+// pendingChange is a change output of an accepted transfer that the API may not list yet.
+type pendingChange struct {
+	txo     MneeTxo
+	expires time.Time
+}
+
+// utxoReservations tracks the outpoints locked by in-flight transfers, the
+// outpoints spent by accepted ones and their chainable change.
+type utxoReservations struct {
+	ttl         time.Duration
+	chainChange bool
+
+	mutex    sync.Mutex
+	reserved map[string]time.Time
+	spent    map[string]time.Time
+	change   map[string]pendingChange
+}
+
+// available removes reserved and spent UTXOs from the candidates and, when
+// change is chained and the inputs were fetched from the API, appends the
+// pending change owned by the addresses.
+func (r *utxoReservations) available(candidates []MneeTxo, addresses []string, withChange bool) []MneeTxo {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.expire(time.Now())
+
+	var available []MneeTxo = make([]MneeTxo, 0, len(candidates))
+	var seen map[string]bool = make(map[string]bool)
+	for _, txo := range candidates {
+		var outpoint string = txoOutpoint(txo)
+		if r.isLocked(outpoint) {
+			continue
+		}
+
+		seen[outpoint] = true
+		available = append(available, txo)
+	}
+
+	if !r.chainChange || !withChange {
+		return available
+	}
+
+	for outpoint, change := range r.change {
+		if !seen[outpoint] && !r.isLocked(outpoint) && slices.Contains(addresses, change.txo.Owners[0]) {
+			available = append(available, change.txo)
+		}
+	}
+
+	return available
+}
+
+// reserve locks the outpoints of the inputs. It reports false and locks
+// nothing if any of them was reserved or spent in the meantime.
+func (r *utxoReservations) reserve(inputs []MneeTxo) bool {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var now time.Time = time.Now()
+	r.expire(now)
+
+	for _, txo := range inputs {
+		if r.isLocked(txoOutpoint(txo)) {
+			return false
+		}
+	}
+
+	for _, txo := range inputs {
+		r.reserved[txoOutpoint(txo)] = now.Add(r.ttl)
+	}
+
+	return true
+}
+
+// release unlocks the outpoints of the inputs.
+func (r *utxoReservations) release(inputs []MneeTxo) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, txo := range inputs {
+		delete(r.reserved, txoOutpoint(txo))
+	}
+}
+
+// settle records the outcome of a submission. Accepted transfers mark their
+// inputs spent and, given the cosigned transaction, add their change outputs
+// as pending change. Transfers the cosigner rejected with a 4xx are released;
+// on other errors the transfer may have landed, so the inputs stay reserved until the TTL.
+func (r *utxoReservations) settle(summary *TransferSummary, submitted *transaction.Transaction, cosignedHex *string, err error) {
+
+	if err != nil {
+		if isRejected(err) {
+			r.release(summary.Inputs)
+		}
+
+		return
+	}
+
+	var changeTxos []MneeTxo
+	if r.chainChange && cosignedHex != nil {
+		changeTxos = outputTxos(summary, submitted, *cosignedHex, OutputChange)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var expires time.Time = time.Now().Add(r.ttl)
+	for _, txo := range summary.Inputs {
+		var outpoint string = txoOutpoint(txo)
+		delete(r.reserved, outpoint)
+		delete(r.change, outpoint)
+		r.spent[outpoint] = expires
+	}
+
+	for _, txo := range changeTxos {
+		r.change[*txo.Outpoint] = pendingChange{txo: txo, expires: expires}
+	}
+}
+
+// isLocked reports whether an outpoint is reserved or spent. The caller must hold the mutex.
+func (r *utxoReservations) isLocked(outpoint string) bool {
+
+	_, reserved := r.reserved[outpoint]
+	_, spent := r.spent[outpoint]
+	return reserved || spent
+}
+
+// expire drops reservations, spent markers and change older than the TTL.
+// The caller must hold the mutex.
+func (r *utxoReservations) expire(now time.Time) {
+
+	for outpoint, expires := range r.reserved {
+		if now.After(expires) {
+			delete(r.reserved, outpoint)
+		}
+	}
+
+	for outpoint, expires := range r.spent {
+		if now.After(expires) {
+			delete(r.spent, outpoint)
+		}
+	}
+
+	for outpoint, change := range r.change {
+		if now.After(change.expires) {
+			delete(r.change, outpoint)
+		}
+	}
+}
+
+// outputTxos returns the outputs of the given kind of the cosigned transaction
+// as UTXOs. The cosigner keeps the outputs of the submitted transaction in
+// place, so they are matched by index and locking script.
+func outputTxos(summary *TransferSummary, submitted *transaction.Transaction, cosignedHex string, kind TransferOutputKind) []MneeTxo {
+
+	cosigned, err := transaction.NewTransactionFromHex(cosignedHex)
+	if err != nil {
+		return nil
+	}
+
+	var txid string = cosigned.TxID().String()
+	var txos []MneeTxo
+	for vout, output := range summary.Outputs {
+		if output.Kind != kind || vout >= len(cosigned.Outputs) || vout >= len(submitted.Outputs) {
+			continue
+		}
+
+		var lockingScript []byte = cosigned.Outputs[vout].LockingScript.Bytes()
+		if !bytes.Equal(lockingScript, submitted.Outputs[vout].LockingScript.Bytes()) {
+			continue
+		}
+
+		var outpoint string = fmt.Sprintf("%s_%d", txid, vout)
+		var encodedScript string = base64.StdEncoding.EncodeToString(lockingScript)
+		txos = append(txos, MneeTxo{
+			Satoshis: uint16(cosigned.Outputs[vout].Satoshis),
+			Vout:     uint64(vout),
+			Outpoint: &outpoint,
+			Script:   &encodedScript,
+			Txid:     &txid,
+			Owners:   []string{output.Address},
+			Data: &Data{
+				Bsv21: &BsvData{Amt: output.Amount, Decimals: summary.Decimals},
+			},
+		})
+	}
+
+	return txos
+}
+
+// txoOutpoint returns the "txid_vout" outpoint of a UTXO.
+func txoOutpoint(txo MneeTxo) string {
+
+	if txo.Outpoint != nil {
+		return *txo.Outpoint
+	}
+
+	return fmt.Sprintf("%s_%d", *txo.Txid, txo.Vout)
+}
@@ -0,0 +1,129 @@
+package mnee
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrentTransfers sends `count` transfers of 500 from the sender at once
+// and returns how many failed.
+func concurrentTransfers(m *MNEE, sender testKey, recipient string, count int) int {
+	var wait sync.WaitGroup
+	var mutex sync.Mutex
+	var failures int
+
+	for range count {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			_, err := m.SynchronousTransfer(context.Background(), []string{sender.wif},
+				[]TransferMneeDTO{{Address: recipient, Amount: 500}}, false, nil)
+			if err != nil {
+				mutex.Lock()
+				failures++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wait.Wait()
+	return failures
+}
+
+func TestUTXOReservation_ConcurrentTransfers(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	txos := []MneeTxo{
+		newTestTxo(t, sender.address, 1000, 1),
+		newTestTxo(t, sender.address, 1000, 2),
+		newTestTxo(t, sender.address, 1000, 3),
+		newTestTxo(t, sender.address, 1000, 4),
+	}
+
+	t.Log("Test Case 1: Without reservation concurrent transfers pick the same UTXO...")
+	fixture := newTestFixture(t, txos)
+	assertions.Equal(3, concurrentTransfers(fixture.mnee, sender, recipient.address, 4))
+
+	t.Log("Test Case 2: With reservation every transfer gets its own UTXO...")
+	fixture = newTestFixture(t, txos)
+	require.NoError(t, WithUTXOReservation(ReservationOptions{})(fixture.mnee))
+	assertions.Equal(0, concurrentTransfers(fixture.mnee, sender, recipient.address, 4))
+	assertions.Len(fixture.submitted, 4)
+	assertions.Empty(fixture.mnee.reservations.reserved, "accepted transfers are no longer reserved")
+	assertions.Len(fixture.mnee.reservations.spent, 4)
+
+	t.Log("Test Case 3: Once every UTXO is spent the balance is insufficient...")
+	_, err := fixture.mnee.SynchronousTransfer(context.Background(), []string{sender.wif},
+		[]TransferMneeDTO{{Address: recipient.address, Amount: 500}}, false, nil)
+	assertions.ErrorIs(err, ErrInsufficientMneeBalance)
+}
+
+func TestUTXOReservation_Settle(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	txo := newTestTxo(t, sender.address, 5000, 1)
+
+	t.Log("Test Case 1: Rejected transfers release their UTXOs...")
+	fixture := newTestFixture(t, []MneeTxo{txo})
+	require.NoError(t, WithUTXOReservation(ReservationOptions{})(fixture.mnee))
+	fixture.submitted[*txo.Outpoint] = true
+	_, err := fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 1000).SubmitSync(context.Background())
+	var apiError *APIError
+	assertions.ErrorAs(err, &apiError)
+	assertions.Empty(fixture.mnee.reservations.reserved)
+
+	t.Log("Test Case 2: Transfers with an unknown outcome stay reserved...")
+	reservations := fixture.mnee.reservations
+	summary := &TransferSummary{Inputs: []MneeTxo{txo}}
+	assertions.True(reservations.reserve(summary.Inputs))
+	reservations.settle(summary, nil, nil, errors.New("connection reset"))
+	assertions.Contains(reservations.reserved, *txo.Outpoint)
+	assertions.False(reservations.reserve(summary.Inputs), "reserved UTXOs cannot be reserved twice")
+	reservations.release(summary.Inputs)
+	assertions.True(reservations.reserve(summary.Inputs))
+}
+
+func TestUTXOReservation_ChainChange(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	txos := []MneeTxo{newTestTxo(t, sender.address, 5000, 1)}
+
+	t.Log("Test Case 1: The change of a pending transfer funds the next one...")
+	fixture := newTestFixture(t, txos)
+	require.NoError(t, WithUTXOReservation(ReservationOptions{ChainChange: true})(fixture.mnee))
+
+	first, err := fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 1000).SubmitSync(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	second, err := fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 1000).SubmitSync(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+
+	chained, err := transaction.NewTransactionFromHex(*second.Txhex)
+	if assertions.NoError(err) && assertions.Len(chained.Inputs, 1) {
+		assertions.Equal(*first.Txid, chained.Inputs[0].SourceTXID.String())
+		assertions.Equal(uint32(2), chained.Inputs[0].SourceTxOutIndex, "change follows the recipient and fee outputs")
+	}
+
+	t.Log("Test Case 2: Without chaining the next transfer waits for the API...")
+	fixture = newTestFixture(t, txos)
+	require.NoError(t, WithUTXOReservation(ReservationOptions{})(fixture.mnee))
+	_, err = fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 1000).SubmitSync(context.Background())
+	assertions.NoError(err)
+	_, err = fixture.mnee.NewTransferBuilder().WithWIFs(sender.wif).AddRecipient(recipient.address, 1000).SubmitSync(context.Background())
+	assertions.ErrorIs(err, ErrInsufficientMneeBalance)
+}
@@ -0,0 +1,130 @@
+package mnee
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries failed requests.
+//
+// Retries are applied automatically to idempotent calls (config, UTXO, balance,
+// history, transaction and ticket lookups). Transfer submissions are only retried
+// when SafeSubmit is enabled, in which case every retry re-sends the exact same
+// signed transaction (keyed on its txid) after checking whether its inputs were
+// already spent. The cosigner changes the txid, so the landed transaction is found
+// in the history of the input owners and a retried submit can never double-send.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 are treated as 1 (no retries).
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed backoff between attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt.
+	Multiplier float64
+	// Jitter randomly shortens each backoff by up to this fraction (0 to 1).
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP statuses that trigger a retry.
+	// Network errors are always retryable.
+	RetryableStatusCodes []int
+	// RespectRetryAfter makes the client wait for the server's Retry-After
+	// header (when present) instead of the computed backoff.
+	RespectRetryAfter bool
+	// SafeSubmit enables retries for SynchronousTransfer, AsynchronousTransfer,
+	// SubmitRawTxSync and SubmitRawTxAsync.
+	SafeSubmit bool
+}
+
+// DefaultRetryPolicy returns the policy used by NewMneeInstance: 3 attempts with
+// exponential backoff for 429, 502, 503 and 504 responses and network errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy.
+// Use RetryPolicy{MaxAttempts: 1} to disable retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(m *MNEE) error {
+		m.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// retryable reports whether a failed attempt may be retried under the policy.
+func (p *RetryPolicy) retryable(err error) bool {
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return slices.Contains(p.RetryableStatusCodes, apiError.StatusCode)
+	}
+
+	return true
+}
+
+// backoff returns how long to wait after the given (1-based) failed attempt.
+func (p *RetryPolicy) backoff(attempt int, err error) time.Duration {
+
+	var apiError *APIError
+	if p.RespectRetryAfter && errors.As(err, &apiError) && apiError.RetryAfter > 0 {
+		return apiError.RetryAfter
+	}
+
+	var multiplier float64 = p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	var wait float64 = float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		wait -= wait * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(wait)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
@@ -0,0 +1,211 @@
+package mnee
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/chainhash"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryPolicy_IdempotentCalls(t *testing.T) {
+	assertions := assert.New(t)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+	if !assertions.NoError(err) {
+		return
+	}
+
+	t.Log("Test Case 1: A read succeeds after two 503 responses...")
+	txos, err := m.GetUnspentTxos(context.Background(), []string{"address"})
+	assertions.NoError(err)
+	assertions.Empty(txos)
+	assertions.Equal(int32(3), attempts.Load())
+
+	t.Log("Test Case 2: Retries stop after MaxAttempts...")
+	attempts.Store(-10)
+	_, err = m.GetBalances(context.Background(), []string{"address"})
+	assertions.ErrorIs(err, ErrServerUnavailable)
+	assertions.Equal(int32(-7), attempts.Load(), "Should have made exactly 3 attempts")
+
+	t.Log("Test Case 3: Non-retryable statuses are returned immediately...")
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	attempts.Store(0)
+	_, err = m.GetBalances(context.Background(), []string{"address"})
+	assertions.Error(err)
+	assertions.Equal(int32(1), attempts.Load())
+}
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	assertions := assert.New(t)
+
+	policy := testRetryPolicy()
+	err := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}
+	assertions.True(policy.retryable(err))
+	assertions.Equal(2*time.Second, policy.backoff(1, err))
+
+	policy.RespectRetryAfter = false
+	assertions.LessOrEqual(policy.backoff(1, err), time.Millisecond)
+
+	assertions.Equal(3*time.Second, parseRetryAfter("3"))
+	assertions.Equal(time.Duration(0), parseRetryAfter("soon"))
+	assertions.False(policy.retryable(context.Canceled))
+}
+
+// testSubmission returns a transaction spending outpoint, and the same
+// transaction with an extra signature pushed onto its unlocking script and a
+// funding input placed before it, as the cosigner returns it.
+func testSubmission(sourceTxid string, outputs ...uint64) (*transaction.Transaction, *transaction.Transaction) {
+
+	build := func(unlocking string, funded bool) *transaction.Transaction {
+		tx := transaction.NewTransaction()
+		sourceHash, _ := chainhash.NewHashFromHex(sourceTxid)
+		unlockingScript, _ := script.NewFromHex(unlocking)
+		if funded {
+			fundingHash, _ := chainhash.NewHashFromHex(strings.Repeat("cd", 32))
+			tx.AddInput(&transaction.TransactionInput{SourceTXID: fundingHash, SourceTxOutIndex: 3, UnlockingScript: unlockingScript})
+		}
+		tx.AddInput(&transaction.TransactionInput{SourceTXID: sourceHash, SourceTxOutIndex: 0, UnlockingScript: unlockingScript})
+		for _, satoshis := range outputs {
+			lockingScript, _ := script.NewFromHex("76a914" + strings.Repeat("11", 20) + "88ac")
+			tx.AddOutput(&transaction.TransactionOutput{Satoshis: satoshis, LockingScript: lockingScript})
+		}
+		return tx
+	}
+
+	return build("0101", false), build("01020101", true)
+}
+
+func TestRetryPolicy_SafeSubmit(t *testing.T) {
+	assertions := assert.New(t)
+
+	sourceTxid := strings.Repeat("ab", 32)
+	submitted, cosigned := testSubmission(sourceTxid, 1, 1)
+	_, conflicting := testSubmission(sourceTxid, 1)
+	assertions.NotEqual(submitted.TxID().String(), cosigned.TxID().String())
+
+	var submits atomic.Int32
+	var spender atomic.Pointer[transaction.Transaction]
+	var txoStatus atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/transfer", "/v2/transfer":
+			submits.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/v2/txos/" + sourceTxid + "_0":
+			if status := txoStatus.Load(); status != 0 {
+				w.WriteHeader(int(status))
+				return
+			}
+			_, _ = w.Write([]byte(`{"outpoint":"` + sourceTxid + `_0","owners":["owner"]}`))
+		case "/v1/utxos", "/v2/utxos":
+			if spender.Load() == nil {
+				_, _ = w.Write([]byte(`[{"outpoint":"` + sourceTxid + `_0","owners":["owner"]}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		case "/v1/sync":
+			if spent := spender.Load(); spent != nil {
+				_, _ = w.Write([]byte(`[{"txid":"` + spent.TxID().String() + `","rawtx":"` +
+					base64.StdEncoding.EncodeToString(spent.Bytes()) + `"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Log("Test Case 1: Without SafeSubmit a failed submission is not retried...")
+	m, err := NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+	if !assertions.NoError(err) {
+		return
+	}
+	_, err = m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	assertions.ErrorIs(err, ErrServerUnavailable)
+	assertions.Equal(int32(1), submits.Load())
+
+	t.Log("Test Case 2: With SafeSubmit unspent inputs are re-sent...")
+	policy := testRetryPolicy()
+	policy.SafeSubmit = true
+	m, err = NewMneeInstance(EnvCustom, "token", WithBaseURL(server.URL), WithRetryPolicy(policy))
+	if !assertions.NoError(err) {
+		return
+	}
+	submits.Store(0)
+
+	_, err = m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	assertions.ErrorIs(err, ErrServerUnavailable)
+	assertions.Equal(int32(policy.MaxAttempts), submits.Load())
+
+	t.Log("Test Case 3: The cosigned transaction is returned once it spent the inputs...")
+	spender.Store(cosigned)
+	submits.Store(0)
+
+	response, err := m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(cosigned.TxID().String(), *response.Txid)
+	assertions.Equal(cosigned.Hex(), *response.Txhex)
+	assertions.Equal(int32(1), submits.Load(), "The transaction must not be re-sent once it landed")
+
+	submits.Store(0)
+	_, err = m.SubmitRawTxAsync(context.Background(), submitted.Hex(), nil, nil)
+	assertions.ErrorIs(err, ErrTransferAlreadySubmitted)
+	assertions.Contains(err.Error(), cosigned.TxID().String())
+	assertions.Equal(int32(1), submits.Load())
+
+	t.Log("Test Case 4: A conflicting spend stops the retries...")
+	spender.Store(conflicting)
+	submits.Store(0)
+
+	_, err = m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	assertions.ErrorIs(err, ErrInputsSpent)
+	assertions.Equal(int32(1), submits.Load())
+
+	t.Log("Test Case 5: Lookup errors other than not found are returned...")
+	txoStatus.Store(http.StatusForbidden)
+	submits.Store(0)
+
+	_, err = m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	assertions.ErrorIs(err, ErrForbidden)
+	assertions.Equal(int32(1), submits.Load())
+
+	t.Log("Test Case 6: Inputs unknown to the API are re-sent...")
+	txoStatus.Store(http.StatusNotFound)
+	submits.Store(0)
+
+	_, err = m.SubmitRawTxSync(context.Background(), submitted.Hex())
+	assertions.ErrorIs(err, ErrServerUnavailable)
+	assertions.Equal(int32(policy.MaxAttempts), submits.Load())
+}
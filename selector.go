@@ -0,0 +1,232 @@
+package mnee
+
+import (
+	"cmp"
+	"slices"
+)
+
+// CoinSelector decides which MNEE UTXOs a transfer spends and in which order.
+//
+// The builder passes the spendable candidates (owned by the transfer's addresses)
+// and the target amount, which is the transfer amount plus the estimated fee.
+// The builder then consumes the returned UTXOs in order until the amount and fee
+// are covered; UTXOs left out of the result are never spent. The estimate exempts
+// every spendable address from the fee, but only the selected inputs' addresses
+// are exempt, so when the result does not cover the actual fee Select is called
+// again with a higher target.
+type CoinSelector interface {
+	// Name identifies the strategy; it is reported in TransferSummary.CoinSelector.
+	Name() string
+	// Select returns the UTXOs to spend, in spending order.
+	Select(candidates []MneeTxo, target uint64) []MneeTxo
+}
+
+// APIOrderSelector spends UTXOs in the order the API returned them.
+// It is the default strategy.
+type APIOrderSelector struct{}
+
+// Name implements CoinSelector.
+func (APIOrderSelector) Name() string {
+	return "api-order"
+}
+
+// Select implements CoinSelector.
+func (APIOrderSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+	return candidates
+}
+
+// LargestFirstSelector spends the largest UTXOs first, minimising the number of inputs.
+type LargestFirstSelector struct{}
+
+// Name implements CoinSelector.
+func (LargestFirstSelector) Name() string {
+	return "largest-first"
+}
+
+// Select implements CoinSelector.
+func (LargestFirstSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+
+	var selected []MneeTxo = slices.Clone(candidates)
+	slices.SortStableFunc(selected, func(a MneeTxo, b MneeTxo) int {
+		return cmp.Compare(txoAmount(b), txoAmount(a))
+	})
+
+	return selected
+}
+
+// SmallestFirstSelector spends the smallest UTXOs first, consolidating dust.
+type SmallestFirstSelector struct{}
+
+// Name implements CoinSelector.
+func (SmallestFirstSelector) Name() string {
+	return "smallest-first"
+}
+
+// Select implements CoinSelector.
+func (SmallestFirstSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+
+	var selected []MneeTxo = slices.Clone(candidates)
+	slices.SortStableFunc(selected, func(a MneeTxo, b MneeTxo) int {
+		return cmp.Compare(txoAmount(a), txoAmount(b))
+	})
+
+	return selected
+}
+
+// OldestFirstSelector spends UTXOs by ascending block Height.
+// Unconfirmed UTXOs (Height 0) are spent last.
+type OldestFirstSelector struct{}
+
+// Name implements CoinSelector.
+func (OldestFirstSelector) Name() string {
+	return "oldest-first"
+}
+
+// Select implements CoinSelector.
+func (OldestFirstSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+
+	var selected []MneeTxo = slices.Clone(candidates)
+	slices.SortStableFunc(selected, func(a MneeTxo, b MneeTxo) int {
+		if (a.Height == 0) != (b.Height == 0) {
+			if a.Height == 0 {
+				return 1
+			}
+			return -1
+		}
+
+		return cmp.Compare(a.Height, b.Height)
+	})
+
+	return selected
+}
+
+// defaultBranchAndBoundTries bounds the search of BranchAndBoundSelector.
+const defaultBranchAndBoundTries int = 100000
+
+// BranchAndBoundSelector searches for a set of UTXOs whose amounts add up to
+// exactly the target, so that no change output is needed. When no exact match
+// is found within MaxTries steps it falls back to largest-first.
+type BranchAndBoundSelector struct {
+	// MaxTries bounds the search. Zero uses a default of 100000.
+	MaxTries int
+}
+
+// Name implements CoinSelector.
+func (BranchAndBoundSelector) Name() string {
+	return "branch-and-bound"
+}
+
+// Select implements CoinSelector.
+func (s BranchAndBoundSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+
+	var sorted []MneeTxo = LargestFirstSelector{}.Select(candidates, target)
+
+	var maxTries int = s.MaxTries
+	if maxTries <= 0 {
+		maxTries = defaultBranchAndBoundTries
+	}
+
+	// remaining[i] is the sum of sorted[i:], used to prune branches that cannot reach the target.
+	var remaining []uint64 = make([]uint64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + txoAmount(sorted[i])
+	}
+
+	var chosen []int = make([]int, 0)
+	var tries int
+
+	var search func(index int, sum uint64) bool
+	search = func(index int, sum uint64) bool {
+		if sum == target {
+			return true
+		}
+
+		tries++
+		if index >= len(sorted) || tries > maxTries || sum+remaining[index] < target {
+			return false
+		}
+
+		if amount := txoAmount(sorted[index]); sum+amount <= target {
+			chosen = append(chosen, index)
+			if search(index+1, sum+amount) {
+				return true
+			}
+			chosen = chosen[:len(chosen)-1]
+		}
+
+		return search(index+1, sum)
+	}
+
+	if target == 0 || !search(0, 0) {
+		return sorted
+	}
+
+	// The exact match is spent first; the rest follows in case the fee differs from the estimate.
+	var selected []MneeTxo = make([]MneeTxo, 0, len(sorted))
+	for _, index := range chosen {
+		selected = append(selected, sorted[index])
+	}
+
+	for i := range sorted {
+		if !slices.Contains(chosen, i) {
+			selected = append(selected, sorted[i])
+		}
+	}
+
+	return selected
+}
+
+// SingleAddressSelector only spends UTXOs from a single address, so a transfer
+// never links several of the wallet's addresses together. It picks the address
+// with the smallest balance that still covers the target and returns nothing
+// (insufficient balance) when no single address can.
+type SingleAddressSelector struct{}
+
+// Name implements CoinSelector.
+func (SingleAddressSelector) Name() string {
+	return "single-address"
+}
+
+// Select implements CoinSelector.
+func (SingleAddressSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+
+	var balances map[string]uint64 = make(map[string]uint64)
+	for _, txo := range candidates {
+		balances[txo.Owners[0]] += txoAmount(txo)
+	}
+
+	var chosenAddress string
+	for address, balance := range balances {
+		if balance < target {
+			continue
+		}
+
+		if chosenAddress == "" || balance < balances[chosenAddress] ||
+			(balance == balances[chosenAddress] && address < chosenAddress) {
+			chosenAddress = address
+		}
+	}
+
+	if chosenAddress == "" {
+		return nil
+	}
+
+	var selected []MneeTxo = make([]MneeTxo, 0)
+	for _, txo := range candidates {
+		if txo.Owners[0] == chosenAddress {
+			selected = append(selected, txo)
+		}
+	}
+
+	return LargestFirstSelector{}.Select(selected, target)
+}
+
+// txoAmount returns the MNEE amount held by the UTXO.
+func txoAmount(txo MneeTxo) uint64 {
+
+	if txo.Data == nil || txo.Data.Bsv21 == nil {
+		return 0
+	}
+
+	return txo.Data.Bsv21.Amt
+}
@@ -0,0 +1,108 @@
+package mnee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func txoAmounts(txos []MneeTxo) []uint64 {
+	amounts := make([]uint64, 0, len(txos))
+	for _, txo := range txos {
+		amounts = append(amounts, txoAmount(txo))
+	}
+	return amounts
+}
+
+func TestCoinSelectors(t *testing.T) {
+	assertions := assert.New(t)
+
+	first := newTestKey(t)
+	second := newTestKey(t)
+	candidates := []MneeTxo{
+		newTestTxo(t, first.address, 500, 30),
+		newTestTxo(t, first.address, 2000, 0),
+		newTestTxo(t, second.address, 700, 10),
+		newTestTxo(t, second.address, 300, 20),
+		newTestTxo(t, first.address, 1200, 40),
+	}
+
+	t.Log("Test Case 1: Ordering strategies...")
+	assertions.Equal([]uint64{500, 2000, 700, 300, 1200}, txoAmounts(APIOrderSelector{}.Select(candidates, 1000)))
+	assertions.Equal([]uint64{2000, 1200, 700, 500, 300}, txoAmounts(LargestFirstSelector{}.Select(candidates, 1000)))
+	assertions.Equal([]uint64{300, 500, 700, 1200, 2000}, txoAmounts(SmallestFirstSelector{}.Select(candidates, 1000)))
+	assertions.Equal([]uint64{700, 300, 500, 1200, 2000}, txoAmounts(OldestFirstSelector{}.Select(candidates, 1000)),
+		"Unconfirmed UTXOs should be spent last")
+
+	t.Log("Test Case 2: Branch and bound finds an exact match...")
+	selected := BranchAndBoundSelector{}.Select(candidates, 1500)
+	assertions.Equal([]uint64{1200, 300}, txoAmounts(selected[:2]))
+	assertions.Len(selected, len(candidates), "Remaining UTXOs should follow as a fallback")
+
+	selected = BranchAndBoundSelector{}.Select(candidates, 99999)
+	assertions.Equal(txoAmounts(LargestFirstSelector{}.Select(candidates, 99999)), txoAmounts(selected))
+
+	t.Log("Test Case 3: Single address picks the smallest sufficient wallet...")
+	selected = SingleAddressSelector{}.Select(candidates, 900)
+	assertions.Equal([]uint64{700, 300}, txoAmounts(selected))
+	selected = SingleAddressSelector{}.Select(candidates, 1100)
+	assertions.Equal([]uint64{2000, 1200, 500}, txoAmounts(selected))
+	assertions.Empty(SingleAddressSelector{}.Select(candidates, 5000))
+}
+
+func TestTransferBuilder_CoinSelector(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 100, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+		newTestTxo(t, sender.address, 1100, 3),
+		newTestTxo(t, sender.address, 900, 4),
+	})
+
+	t.Log("Test Case 1: The default strategy keeps the API order...")
+	_, summary, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 1000).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal("api-order", summary.CoinSelector)
+	assertions.Equal([]uint64{100, 5000}, txoAmounts(summary.Inputs))
+
+	t.Log("Test Case 2: Branch and bound avoids change...")
+	_, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 1000).
+		WithCoinSelector(BranchAndBoundSelector{}).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal("branch-and-bound", summary.CoinSelector)
+	assertions.Equal([]uint64{1100}, txoAmounts(summary.Inputs))
+	assertions.Equal(uint64(0), summary.Change)
+	assertions.Len(summary.Outputs, 2, "Recipient and fee only")
+
+	t.Log("Test Case 3: The selection is redone when it does not cover the fee it pays...")
+	other := newTestKey(t)
+	fixture = newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 1500500, 1),
+		newTestTxo(t, other.address, 1600000, 2),
+	})
+	_, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif, other.wif).
+		AddRecipient(other.address, 1500000).
+		WithCoinSelector(SingleAddressSelector{}).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal([]uint64{1600000}, txoAmounts(summary.Inputs), "The sender cannot pay the fee of a 1500000 transfer")
+	assertions.Equal(uint64(100), summary.Fee)
+	assertions.Equal(uint64(100000-100), summary.Change)
+}
@@ -0,0 +1,441 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// Signer signs MNEE transaction inputs for the addresses it controls, so that
+// private keys never have to be handed to the SDK as WIF strings.
+type Signer interface {
+	// Addresses returns the addresses whose UTXOs the signer can spend.
+	Addresses(ctx context.Context) ([]string, error)
+	// PublicKey returns the public key of one of the signer's addresses.
+	PublicKey(ctx context.Context, address string) (*primitives.PublicKey, error)
+	// Sign signs the signature hash of an input owned by `address`.
+	Sign(ctx context.Context, address string, sighash []byte) (*primitives.Signature, error)
+}
+
+// KeySigner is an in-memory Signer backed by private keys.
+type KeySigner struct {
+	addresses []string
+	keys      map[string]*primitives.PrivateKey
+}
+
+// NewKeySigner creates a Signer from private keys. Addresses are derived from
+// the compressed public keys.
+func NewKeySigner(privateKeys ...*primitives.PrivateKey) (*KeySigner, error) {
+
+	var signer KeySigner = KeySigner{
+		addresses: make([]string, 0, len(privateKeys)),
+		keys:      make(map[string]*primitives.PrivateKey),
+	}
+
+	for _, privateKey := range privateKeys {
+		if privateKey == nil {
+			return nil, ErrNilPrivateKey
+		}
+
+		address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := signer.keys[address.AddressString]; !ok {
+			signer.addresses = append(signer.addresses, address.AddressString)
+		}
+		signer.keys[address.AddressString] = privateKey
+	}
+
+	return &signer, nil
+}
+
+// NewWIFSigner creates a Signer from private keys in Wallet Import Format.
+func NewWIFSigner(wifs ...string) (*KeySigner, error) {
+
+	var privateKeys []*primitives.PrivateKey = make([]*primitives.PrivateKey, 0, len(wifs))
+	for _, wif := range wifs {
+		privateKey, err := primitives.PrivateKeyFromWif(wif)
+		if err != nil {
+			return nil, err
+		}
+
+		privateKeys = append(privateKeys, privateKey)
+	}
+
+	return NewKeySigner(privateKeys...)
+}
+
+// Addresses implements Signer.
+func (s *KeySigner) Addresses(ctx context.Context) ([]string, error) {
+	return slices.Clone(s.addresses), nil
+}
+
+// PublicKey implements Signer.
+func (s *KeySigner) PublicKey(ctx context.Context, address string) (*primitives.PublicKey, error) {
+
+	privateKey, ok := s.keys[address]
+	if !ok {
+		return nil, ErrUnknownSignerAddress
+	}
+
+	return privateKey.PubKey(), nil
+}
+
+// Sign implements Signer.
+func (s *KeySigner) Sign(ctx context.Context, address string, sighash []byte) (*primitives.Signature, error) {
+
+	privateKey, ok := s.keys[address]
+	if !ok {
+		return nil, ErrUnknownSignerAddress
+	}
+
+	return privateKey.Sign(sighash)
+}
+
+// remoteSignerRequest is the JSON body of a RemoteSigner call.
+type remoteSignerRequest struct {
+	Method  string `json:"method"`
+	Address string `json:"address,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+}
+
+// remoteSignerResponse is the JSON body returned by a remote signing service.
+type remoteSignerResponse struct {
+	Addresses []string `json:"addresses,omitempty"`
+	PublicKey string   `json:"publicKey,omitempty"`
+	Signature string   `json:"signature,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+const (
+	remoteMethodAddresses string = "addresses"
+	remoteMethodPublicKey string = "publicKey"
+	remoteMethodSign      string = "sign"
+)
+
+// RemoteSigner is a Signer that delegates to an out-of-process signing service
+// over a simple JSON RPC: every call is a POST to the endpoint with a body of
+// {"method": "addresses" | "publicKey" | "sign", "address": ..., "hash": <hex>},
+// answered with {"addresses": [...]}, {"publicKey": <hex>} or {"signature": <DER hex>},
+// or {"error": ...} with a non-200 status. NewRemoteSignerHandler serves this protocol.
+//
+// The service is not trusted blindly: public keys must hash to the requested
+// address and signatures must verify against them, so a compromised or faulty
+// service cannot slip an invalid unlocking script into a transfer.
+type RemoteSigner struct {
+	endpoint   string
+	httpClient *http.Client
+	token      string
+
+	mutex      sync.Mutex
+	publicKeys map[string]*primitives.PublicKey
+}
+
+// NewRemoteSigner creates a RemoteSigner for the signing service at endpoint.
+// A nil httpClient uses http.DefaultClient. Use WithBearerToken, or a client
+// with mutual TLS, to authenticate to the service.
+func NewRemoteSigner(endpoint string, httpClient *http.Client) *RemoteSigner {
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &RemoteSigner{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		publicKeys: make(map[string]*primitives.PublicKey),
+	}
+}
+
+// WithBearerToken sends the token as "Authorization: Bearer <token>" with every
+// call, to be checked by a handler created with BearerTokenAuthorizer.
+func (s *RemoteSigner) WithBearerToken(token string) *RemoteSigner {
+	s.token = token
+	return s
+}
+
+// Addresses implements Signer.
+func (s *RemoteSigner) Addresses(ctx context.Context) ([]string, error) {
+
+	response, err := s.call(ctx, remoteSignerRequest{Method: remoteMethodAddresses})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Addresses, nil
+}
+
+// PublicKey implements Signer. The key must hash to the address; it is cached
+// for the lifetime of the RemoteSigner.
+func (s *RemoteSigner) PublicKey(ctx context.Context, address string) (*primitives.PublicKey, error) {
+
+	s.mutex.Lock()
+	publicKey, ok := s.publicKeys[address]
+	s.mutex.Unlock()
+	if ok {
+		return publicKey, nil
+	}
+
+	response, err := s.call(ctx, remoteSignerRequest{Method: remoteMethodPublicKey, Address: address})
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err = primitives.PublicKeyFromString(response.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyAddress, err := script.NewAddressFromPublicKey(publicKey, true)
+	if err != nil {
+		return nil, err
+	}
+	if keyAddress.AddressString != address {
+		return nil, fmt.Errorf("%w: public key does not belong to %s", ErrRemoteSigner, address)
+	}
+
+	s.mutex.Lock()
+	s.publicKeys[address] = publicKey
+	s.mutex.Unlock()
+
+	return publicKey, nil
+}
+
+// Sign implements Signer. The returned signature is verified against the
+// public key of the address before it is used.
+func (s *RemoteSigner) Sign(ctx context.Context, address string, sighash []byte) (*primitives.Signature, error) {
+
+	publicKey, err := s.PublicKey(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.call(ctx, remoteSignerRequest{Method: remoteMethodSign, Address: address, Hash: hex.EncodeToString(sighash)})
+	if err != nil {
+		return nil, err
+	}
+
+	signatureBytes, err := hex.DecodeString(response.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := primitives.ParseDERSignature(signatureBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if !signature.Verify(sighash, publicKey) {
+		return nil, fmt.Errorf("%w: signature for %s does not verify", ErrRemoteSigner, address)
+	}
+
+	return signature, nil
+}
+
+// call performs a single RPC round trip with the signing service.
+func (s *RemoteSigner) call(ctx context.Context, rpcRequest remoteSignerRequest) (*remoteSignerResponse, error) {
+
+	requestBody, err := json.Marshal(&rpcRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	signerRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	signerRequest.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		signerRequest.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	signerResponse, err := s.httpClient.Do(signerRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer signerResponse.Body.Close()
+
+	var rpcResponse remoteSignerResponse
+	err = json.NewDecoder(signerResponse.Body).Decode(&rpcResponse)
+	if err != nil && signerResponse.StatusCode == http.StatusOK {
+		return nil, err
+	}
+
+	if signerResponse.StatusCode != http.StatusOK || rpcResponse.Error != "" {
+		return nil, fmt.Errorf("%w: %s (status %d)", ErrRemoteSigner, rpcResponse.Error, signerResponse.StatusCode)
+	}
+
+	return &rpcResponse, nil
+}
+
+// RemoteSignerAuthorizer authenticates a request to a handler created by
+// NewRemoteSignerHandler. A non-nil error rejects it with 401 Unauthorized.
+type RemoteSignerAuthorizer func(r *http.Request) error
+
+// BearerTokenAuthorizer accepts requests carrying "Authorization: Bearer <token>",
+// as sent by RemoteSigner.WithBearerToken. The token is compared in constant time.
+func BearerTokenAuthorizer(token string) RemoteSignerAuthorizer {
+	return func(r *http.Request) error {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			return ErrUnauthorized
+		}
+
+		return nil
+	}
+}
+
+// NewRemoteSignerHandler exposes a Signer over the RemoteSigner protocol, so a
+// separate signing service can hold the keys and serve transfer builders remotely.
+//
+// The handler signs any hash it is sent for the signer's addresses: whoever can
+// reach it can spend the signer's funds. Every request is therefore checked by
+// authorize (e.g. BearerTokenAuthorizer, or a check of the mutual TLS peer)
+// before it is decoded, and authorize is required. Serve the handler only over
+// TLS on a private network.
+func NewRemoteSignerHandler(signer Signer, authorize RemoteSignerAuthorizer) (http.Handler, error) {
+
+	if authorize == nil {
+		return nil, ErrNilRemoteSignerAuthorizer
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		err := authorize(r)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(&remoteSignerResponse{Error: err.Error()})
+			return
+		}
+
+		var rpcRequest remoteSignerRequest
+		var rpcResponse remoteSignerResponse
+		var status int = http.StatusOK
+
+		err = json.NewDecoder(r.Body).Decode(&rpcRequest)
+		if err == nil {
+			switch rpcRequest.Method {
+
+			case remoteMethodAddresses:
+				rpcResponse.Addresses, err = signer.Addresses(r.Context())
+
+			case remoteMethodPublicKey:
+				var publicKey *primitives.PublicKey
+				publicKey, err = signer.PublicKey(r.Context(), rpcRequest.Address)
+				if err == nil {
+					rpcResponse.PublicKey = hex.EncodeToString(publicKey.Compressed())
+				}
+
+			case remoteMethodSign:
+				var hash []byte
+				var signature *primitives.Signature
+				hash, err = hex.DecodeString(rpcRequest.Hash)
+				if err == nil {
+					signature, err = signer.Sign(r.Context(), rpcRequest.Address, hash)
+				}
+				if err == nil {
+					rpcResponse.Signature = hex.EncodeToString(signature.Serialize())
+				}
+
+			default:
+				err = fmt.Errorf("unknown method %q", rpcRequest.Method)
+			}
+		}
+
+		if err != nil {
+			status = http.StatusBadRequest
+			rpcResponse = remoteSignerResponse{Error: err.Error()}
+		}
+
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(&rpcResponse)
+	}), nil
+}
+
+// signerUnlocker is a p2pkh UnlockingScriptTemplate that signs through a Signer.
+type signerUnlocker struct {
+	ctx          context.Context
+	signer       Signer
+	address      string
+	sighashFlags sighash.Flag
+}
+
+// Sign implements transaction.UnlockingScriptTemplate.
+func (u *signerUnlocker) Sign(tx *transaction.Transaction, inputIndex uint32) (*script.Script, error) {
+
+	if tx.Inputs[inputIndex].SourceTxOutput() == nil {
+		return nil, transaction.ErrEmptyPreviousTx
+	}
+
+	signatureHash, err := tx.CalcInputSignatureHash(inputIndex, u.sighashFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := u.signer.PublicKey(u.ctx, u.address)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := u.signer.Sign(u.ctx, u.address, signatureHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var unlockingScript script.Script
+	err = unlockingScript.AppendPushData(append(signature.Serialize(), uint8(u.sighashFlags)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = unlockingScript.AppendPushData(publicKey.Compressed())
+	if err != nil {
+		return nil, err
+	}
+
+	return &unlockingScript, nil
+}
+
+// EstimateLength implements transaction.UnlockingScriptTemplate.
+func (u *signerUnlocker) EstimateLength(_ *transaction.Transaction, _ uint32) uint32 {
+	return 106
+}
+
+// signerAddresses maps every address of the signers to the signer controlling it.
+func signerAddresses(ctx context.Context, signers []Signer) (map[string]Signer, []string, error) {
+
+	var addressToSigner map[string]Signer = make(map[string]Signer)
+	var addresses []string = make([]string, 0)
+	for _, signer := range signers {
+		signerAddresses, err := signer.Addresses(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, address := range signerAddresses {
+			if _, ok := addressToSigner[address]; ok {
+				continue
+			}
+
+			addressToSigner[address] = signer
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addressToSigner, addresses, nil
+}
@@ -0,0 +1,172 @@
+package mnee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeySigner(t *testing.T) {
+	assertions := assert.New(t)
+
+	key := newTestKey(t)
+	other := newTestKey(t)
+
+	signer, err := NewKeySigner(key.privateKey, key.privateKey)
+	if !assertions.NoError(err) {
+		return
+	}
+
+	addresses, err := signer.Addresses(context.Background())
+	assertions.NoError(err)
+	assertions.Equal([]string{key.address}, addresses, "Duplicate keys should be listed once")
+
+	hash := make([]byte, 32)
+	signature, err := signer.Sign(context.Background(), key.address, hash)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.True(signature.Verify(hash, key.privateKey.PubKey()))
+
+	_, err = signer.Sign(context.Background(), other.address, hash)
+	assertions.ErrorIs(err, ErrUnknownSignerAddress)
+
+	_, err = NewKeySigner(nil)
+	assertions.ErrorIs(err, ErrNilPrivateKey)
+
+	_, err = NewWIFSigner("not-a-wif")
+	assertions.Error(err)
+}
+
+func TestRemoteSigner_TransferBuilder(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, sender.address, 5000, 2),
+	})
+
+	keySigner, err := NewKeySigner(sender.privateKey)
+	if !assertions.NoError(err) {
+		return
+	}
+
+	handler, err := NewRemoteSignerHandler(keySigner, BearerTokenAuthorizer("signing-token"))
+	if !assertions.NoError(err) {
+		return
+	}
+	signingService := httptest.NewServer(handler)
+	defer signingService.Close()
+
+	remoteSigner := NewRemoteSigner(signingService.URL, nil).WithBearerToken("signing-token")
+
+	t.Log("Test Case 1: The remote signer exposes the service's keys...")
+	addresses, err := remoteSigner.Addresses(context.Background())
+	assertions.NoError(err)
+	assertions.Equal([]string{sender.address}, addresses)
+
+	publicKey, err := remoteSigner.PublicKey(context.Background(), sender.address)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.True(publicKey.IsEqual(sender.privateKey.PubKey()))
+
+	_, err = remoteSigner.PublicKey(context.Background(), recipient.address)
+	assertions.ErrorIs(err, ErrRemoteSigner)
+
+	t.Log("Test Case 2: A remotely signed transfer matches the WIF-signed one...")
+	remoteTransaction, _, err := fixture.mnee.NewTransferBuilder().
+		WithSigner(remoteSigner).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+
+	wifTransaction, _, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(wifTransaction.Hex(), remoteTransaction.Hex())
+
+	t.Log("Test Case 3: The handler requires an authorizer and rejects unauthenticated calls...")
+	_, err = NewRemoteSignerHandler(keySigner, nil)
+	assertions.ErrorIs(err, ErrNilRemoteSignerAuthorizer)
+
+	for _, token := range []string{"", "wrong-token"} {
+		_, err = NewRemoteSigner(signingService.URL, nil).WithBearerToken(token).Sign(context.Background(), sender.address, make([]byte, 32))
+		assertions.ErrorIs(err, ErrRemoteSigner)
+		assertions.Contains(err.Error(), "status 401")
+	}
+
+	t.Log("Test Case 4: Keys and signatures that do not match the address are refused...")
+	impostor := newTestKey(t)
+	for _, forged := range []Signer{
+		forgedSigner{Signer: keySigner, publicKey: impostor.privateKey},
+		forgedSigner{Signer: keySigner, signature: impostor.privateKey},
+	} {
+		handler, err := NewRemoteSignerHandler(forged, func(r *http.Request) error { return nil })
+		if !assertions.NoError(err) {
+			return
+		}
+		forgingService := httptest.NewServer(handler)
+		_, _, err = fixture.mnee.NewTransferBuilder().
+			WithSigner(NewRemoteSigner(forgingService.URL, nil)).
+			AddRecipient(recipient.address, 4000).
+			Sign(context.Background())
+		assertions.ErrorIs(err, ErrRemoteSigner)
+		forgingService.Close()
+	}
+
+	t.Log("Test Case 5: Inputs of addresses without a signer stay unsigned...")
+	partialTransaction, _, err := fixture.mnee.NewTransferBuilder().
+		WithAddresses(sender.address).
+		WithSigner(mustKeySigner(t, recipient.privateKey)).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	for _, input := range partialTransaction.Inputs {
+		assertions.Nil(input.UnlockingScript)
+	}
+}
+
+func mustKeySigner(t *testing.T, privateKeys ...*primitives.PrivateKey) *KeySigner {
+	signer, err := NewKeySigner(privateKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// forgedSigner answers for the wrapped signer's addresses with another key's
+// public key or signatures, like a compromised signing service.
+type forgedSigner struct {
+	Signer
+	publicKey *primitives.PrivateKey
+	signature *primitives.PrivateKey
+}
+
+func (s forgedSigner) PublicKey(ctx context.Context, address string) (*primitives.PublicKey, error) {
+	if s.publicKey != nil {
+		return s.publicKey.PubKey(), nil
+	}
+	return s.Signer.PublicKey(ctx, address)
+}
+
+func (s forgedSigner) Sign(ctx context.Context, address string, sighash []byte) (*primitives.Signature, error) {
+	if s.signature != nil {
+		return s.signature.Sign(sighash)
+	}
+	return s.Signer.Sign(ctx, address, sighash)
+}
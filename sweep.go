@@ -0,0 +1,171 @@
+package mnee
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// DefaultConsolidateMinInputs is the fewest UTXOs Consolidate merges in one transaction.
+	DefaultConsolidateMinInputs int = 2
+	// DefaultConsolidateMaxInputs caps the UTXOs Consolidate merges in one transaction.
+	DefaultConsolidateMaxInputs int = 100
+)
+
+// ConsolidateOptions configures Consolidate. Zero fields take the defaults.
+type ConsolidateOptions struct {
+	// Threshold selects the UTXOs holding less than this many atomic units.
+	// Zero consolidates every UTXO.
+	Threshold uint64
+	// MinInputs skips addresses with fewer small UTXOs. Defaults to DefaultConsolidateMinInputs.
+	MinInputs int
+	// MaxInputs bounds the size of each consolidation transaction; addresses with
+	// more small UTXOs get several. Defaults to DefaultConsolidateMaxInputs.
+	MaxInputs int
+}
+
+// ConsolidationResult describes one consolidation transaction.
+type ConsolidationResult struct {
+	Address  string               `json:"address"`
+	Summary  *TransferSummary     `json:"summary"`
+	Response *TransferResponseDTO `json:"response"`
+}
+
+// Consolidate merges the small UTXOs of each of the signer's addresses into a
+// single output at the same address, submitting every transaction with
+// SubmitSync. As a transfer back to an input address, each one only pays the
+// fee tier of a zero amount; groups worth no more than that fee are skipped.
+// It returns the consolidations made before any error.
+func (m *MNEE) Consolidate(ctx context.Context, signer Signer, options ConsolidateOptions) (_ []ConsolidationResult, err error) {
+
+	ctx, span := m.startSpan(ctx, "Consolidate")
+	defer func() { span.End(err) }()
+
+	if signer == nil {
+		return nil, ErrNilSigner
+	}
+	if options.MinInputs <= 0 {
+		options.MinInputs = DefaultConsolidateMinInputs
+	}
+	if options.MaxInputs <= 0 {
+		options.MaxInputs = DefaultConsolidateMaxInputs
+	}
+	options.MinInputs = min(options.MinInputs, options.MaxInputs)
+
+	addresses, err := signer.Addresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var small map[string][]MneeTxo = make(map[string][]MneeTxo)
+	for txo, err := range m.AllUnspentTxos(ctx, addresses, PageOptions{}) {
+		if err != nil {
+			return nil, err
+		}
+
+		if len(txo.Owners) > 0 && (options.Threshold == 0 || txoAmount(txo) < options.Threshold) {
+			small[txo.Owners[0]] = append(small[txo.Owners[0]], txo)
+		}
+	}
+
+	var results []ConsolidationResult = make([]ConsolidationResult, 0)
+	for _, address := range addresses {
+		var txos []MneeTxo = small[address]
+		for start := 0; len(txos)-start >= options.MinInputs; start += options.MaxInputs {
+			var inputs []MneeTxo = txos[start:min(start+options.MaxInputs, len(txos))]
+
+			var total uint64
+			for _, txo := range inputs {
+				total += txoAmount(txo)
+			}
+
+			fee, ok := TieredFeePolicy(config.Fees, 0)
+			if !ok || total <= fee.Fee {
+				continue
+			}
+
+			response, summary, err := m.NewTransferBuilder().WithSigner(signer).WithInputs(inputs).SendAll(address).submitSync(ctx)
+			if err != nil {
+				return results, fmt.Errorf("consolidating %s: %w", address, err)
+			}
+
+			results = append(results, ConsolidationResult{Address: address, Summary: summary, Response: response})
+		}
+	}
+
+	return results, nil
+}
+
+// Sweep moves the whole MNEE balance of the signer's addresses to
+// `destination` in one synchronous transfer, sending the largest amount the
+// fee tiers allow; when a tier boundary leaves a remainder it returns as change.
+// A destination among the signer's addresses only pays the fee tier of a zero
+// amount. It is TransferBuilder.SendAll; wallets with hundreds of UTXOs should Consolidate first.
+func (m *MNEE) Sweep(ctx context.Context, signer Signer, destination string) (_ *TransferResponseDTO, err error) {
+
+	ctx, span := m.startSpan(ctx, "Sweep")
+	defer func() { span.End(err) }()
+
+	if signer == nil {
+		return nil, ErrNilSigner
+	}
+
+	response, _, err := m.NewTransferBuilder().WithSigner(signer).SendAll(destination).submitSync(ctx)
+	return response, err
+}
+
+// solveMaxSendable returns the largest amount that, together with its fee,
+// fits in `available`. `feeBase` is the amount of the other recipients that
+// pay the fee. A fee-bearing amount is added to it to pick the tier, so the
+// upper end of every tier is tried; otherwise only the tier of feeBase applies.
+func solveMaxSendable(available uint64, feeBase uint64, feeBearing bool, fees []Fee, policy FeePolicy) (uint64, bool) {
+
+	if !feeBearing {
+		fee, ok := policy(fees, feeBase)
+		if !ok || available <= fee.Fee {
+			return 0, false
+		}
+
+		return available - fee.Fee, true
+	}
+
+	var best uint64
+	var found bool
+	for _, tier := range fees {
+		if available <= tier.Fee || tier.MaxAmt <= feeBase {
+			continue
+		}
+
+		var amount uint64 = min(available-tier.Fee, tier.MaxAmt-feeBase)
+		if feeBase+amount < tier.MinAmt || (found && amount <= best) {
+			continue
+		}
+
+		fee, ok := policy(fees, feeBase+amount)
+		if !ok || fee.Fee > available-amount {
+			continue
+		}
+
+		best, found = amount, true
+	}
+
+	return best, found
+}
+
+// sweepSelector spends every candidate, in API order.
+type sweepSelector struct{}
+
+// Name returns "sweep".
+func (sweepSelector) Name() string {
+	return "sweep"
+}
+
+// Select returns every candidate.
+func (sweepSelector) Select(candidates []MneeTxo, target uint64) []MneeTxo {
+	return candidates
+}
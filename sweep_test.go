@@ -0,0 +1,83 @@
+package mnee_test
+
+import (
+	"context"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk/mneetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidate(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, address := newPayoutFixture(t, 50000)
+	for range 5 {
+		_, err := server.Fund(address, 300)
+		require.NoError(t, err)
+	}
+
+	t.Log("Test Case 1: UTXOs below the threshold are merged into one output...")
+	results, err := client.Consolidate(context.Background(), signer, mnee.ConsolidateOptions{Threshold: 1000, MaxInputs: 3})
+	if !assertions.NoError(err) {
+		return
+	}
+	if assertions.Len(results, 2, "5 small UTXOs make batches of 3 and 2") {
+		assertions.Len(results[0].Summary.Inputs, 3)
+		assertions.Equal(uint64(100), results[0].Summary.Fee, "self transfers pay the zero amount tier")
+		assertions.Zero(results[0].Summary.Change)
+	}
+
+	var amounts []uint64
+	for _, txo := range server.Unspent() {
+		if txo.Owners[0] == address {
+			amounts = append(amounts, txo.Data.Bsv21.Amt)
+		}
+	}
+	assertions.ElementsMatch([]uint64{50000, 800, 500}, amounts)
+	assertions.Equal(uint64(51300), server.Balance(address))
+
+	t.Log("Test Case 2: Addresses with fewer UTXOs than MinInputs are left alone...")
+	results, err = client.Consolidate(context.Background(), signer, mnee.ConsolidateOptions{Threshold: 1000, MinInputs: 3})
+	assertions.NoError(err)
+	assertions.Empty(results)
+}
+
+func TestSweep(t *testing.T) {
+	assertions := assert.New(t)
+
+	server, client, signer, address := newPayoutFixture(t, 3000)
+	_, err := server.Fund(address, 5000)
+	require.NoError(t, err)
+	destination := newPayoutRecipients(t, 1, 0)[0].Address
+
+	t.Log("Test Case 1: The whole balance minus the fee reaches the destination...")
+	_, err = client.Sweep(context.Background(), signer, destination)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(7900), server.Balance(destination))
+	assertions.Zero(server.Balance(address))
+
+	t.Log("Test Case 2: Tier boundaries cap the amount and leave change...")
+	_, err = server.Fund(address, 1000500)
+	require.NoError(t, err)
+	_, err = client.Sweep(context.Background(), signer, destination)
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Equal(uint64(1007900), server.Balance(destination))
+	assertions.Equal(uint64(400), server.Balance(address))
+	assertions.Equal(2, server.Calls(mneetest.EndpointTransfer))
+
+	t.Log("Test Case 3: Empty wallets cannot be swept...")
+	privateKey, err := primitives.NewPrivateKey()
+	require.NoError(t, err)
+	empty, err := mnee.NewKeySigner(privateKey)
+	require.NoError(t, err)
+	_, err = client.Sweep(context.Background(), empty, destination)
+	assertions.ErrorIs(err, mnee.ErrInsufficientMneeBalance)
+}
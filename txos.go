@@ -1,61 +1,38 @@
 package mnee
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 )
 
 // GetUnspentTxos fetches all MNEE UTXOs for a given list of addresses.
-func (m *MNEE) GetUnspentTxos(ctx context.Context, addresses []string) ([]MneeTxo, error) {
+func (m *MNEE) GetUnspentTxos(ctx context.Context, addresses []string) (_ []MneeTxo, err error) {
+
+	ctx, span := m.startSpan(ctx, "GetUnspentTxos", Attribute{Key: "mnee.address_count", Value: len(addresses)})
+	defer func() { span.End(err) }()
 
 	addressesBuffer, err := json.Marshal(&addresses)
 	if err != nil {
 		return nil, err
 	}
 
-	utxosRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		(m.mneeURL + "/v1/utxos?auth_token=" + m.mneeToken),
-		bytes.NewBuffer(addressesBuffer),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	utxosRequest.Header.Set("Content-Type", "application/json")
-
-	var txos []MneeTxo = make([]MneeTxo, 0)
-	utxosResponse, err := m.httpClient.Do(utxosRequest)
+	utxosResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodPost,
+		endpoint:   "/v1/utxos",
+		body:       addressesBuffer,
+		headers:    map[string]string{"Content-Type": "application/json"},
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	defer utxosResponse.Body.Close()
 
-	if utxosResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if utxosResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(utxosResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", utxosResponse.StatusCode)
-		}
-
-		return nil, errors.New(errorMessage)
-	}
-
+	var txos []MneeTxo = make([]MneeTxo, 0)
 	err = json.NewDecoder(utxosResponse.Body).Decode(&txos)
 	if err != nil {
 		return nil, err
@@ -65,52 +42,31 @@ func (m *MNEE) GetUnspentTxos(ctx context.Context, addresses []string) ([]MneeTx
 }
 
 // GetPaginatedUnspentTxos fetches MNEE UTXOs for a given list of addresses with pagination.
-func (m *MNEE) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) ([]MneeTxo, error) {
+func (m *MNEE) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) (_ []MneeTxo, err error) {
 
-	addressesBuffer, err := json.Marshal(&addresses)
-	if err != nil {
-		return nil, err
-	}
+	ctx, span := m.startSpan(ctx, "GetPaginatedUnspentTxos", Attribute{Key: "mnee.address_count", Value: len(addresses)})
+	defer func() { span.End(err) }()
 
-	utxosRequest, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		(m.mneeURL + "/v2/utxos?page=" + fmt.Sprintf("%d", page) + "&size=" + fmt.Sprintf("%d", size) + "&auth_token=" + m.mneeToken),
-		bytes.NewBuffer(addressesBuffer),
-	)
+	addressesBuffer, err := json.Marshal(&addresses)
 	if err != nil {
 		return nil, err
 	}
 
-	utxosRequest.Header.Set("Content-Type", "application/json")
-
-	var txos []MneeTxo = make([]MneeTxo, 0)
-	utxosResponse, err := m.httpClient.Do(utxosRequest)
+	utxosResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodPost,
+		endpoint:   "/v2/utxos",
+		query:      url.Values{"page": {strconv.Itoa(page)}, "size": {strconv.Itoa(size)}},
+		body:       addressesBuffer,
+		headers:    map[string]string{"Content-Type": "application/json"},
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	defer utxosResponse.Body.Close()
 
-	if utxosResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if utxosResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(utxosResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", utxosResponse.StatusCode)
-		}
-
-		return nil, errors.New(errorMessage)
-	}
-
+	var txos []MneeTxo = make([]MneeTxo, 0)
 	err = json.NewDecoder(utxosResponse.Body).Decode(&txos)
 	if err != nil {
 		return nil, err
@@ -120,43 +76,28 @@ func (m *MNEE) GetPaginatedUnspentTxos(ctx context.Context, addresses []string,
 }
 
 // GetTxo fetches a single MNEE UTXO by its outpoint string (e.g., "txid_vout").
-func (m *MNEE) GetTxo(ctx context.Context, outpoint string) (*MneeTxo, error) {
+func (m *MNEE) GetTxo(ctx context.Context, outpoint string) (_ *MneeTxo, err error) {
+
+	ctx, span := m.startSpan(ctx, "GetTxo", Attribute{Key: "mnee.outpoint", Value: outpoint})
+	defer func() { span.End(err) }()
 
-	utxoRequest, err := http.NewRequest(
-		http.MethodGet,
-		(m.mneeURL + "/v2/txos/" + outpoint + "?auth_token=" + m.mneeToken),
-		nil,
-	)
+	err = ValidateOutpoint(outpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	utxoResponse, err := m.httpClient.Do(utxoRequest)
+	utxoResponse, err := m.do(ctx, &apiRequest{
+		method:     http.MethodGet,
+		endpoint:   "/v2/txos",
+		pathParams: []string{outpoint},
+		idempotent: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	defer utxoResponse.Body.Close()
 
-	if utxoResponse.StatusCode == http.StatusForbidden {
-		return nil, ErrForbidden
-	}
-
-	if utxoResponse.StatusCode != http.StatusOK {
-		var errorResponse map[string]any
-		err = json.NewDecoder(utxoResponse.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-
-		errorMessage, ok := errorResponse["message"].(string)
-		if !ok {
-			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", utxoResponse.StatusCode)
-		}
-
-		return nil, errors.New(errorMessage)
-	}
-
 	var txo MneeTxo
 	err = json.NewDecoder(utxoResponse.Body).Decode(&txo)
 	if err != nil {
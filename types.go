@@ -5,22 +5,110 @@ import (
 	"time"
 )
 
-// ErrForbidden is returned when the MNEE API responds with a 403 Forbidden status.
+// ErrForbidden matches an *APIError for a 403 Forbidden status (use errors.Is).
 // This almost always indicates an invalid or missing API key.
 var ErrForbidden = errors.New("forbidden access to cosigner")
 
+// ErrNotFound matches an *APIError for a 404 Not Found status or a
+// "record not found" response from the MNEE API.
+var ErrNotFound = errors.New("record not found")
+
+// ErrRateLimited matches an *APIError for a 429 Too Many Requests status.
+var ErrRateLimited = errors.New("rate limited by cosigner")
+
+// ErrServerUnavailable matches an *APIError for a 502, 503 or 504 status.
+var ErrServerUnavailable = errors.New("cosigner unavailable")
+
 // ErrInvalidConfig is returned by functions when the fetched
 // MNEE system config is missing required fields (like Approver or TokenId).
 var ErrInvalidConfig = errors.New("invalid config")
 
 // ErrInvalidEnvironment is returned by NewMneeInstance if the
-// environment string is not 'MAIN' or 'SANDBOX'.
+// environment string is not 'MAIN', 'SANDBOX' or 'CUSTOM'.
 var ErrInvalidEnvironment = errors.New("invalid environment")
 
+// ErrMissingBaseURL is returned by NewMneeInstance when the 'CUSTOM'
+// environment is used without providing WithBaseURL.
+var ErrMissingBaseURL = errors.New("custom environment requires a base url")
+
+// ErrInvalidBaseURL is returned by WithBaseURL if the URL is not absolute.
+var ErrInvalidBaseURL = errors.New("invalid base url")
+
+// ErrNilHTTPClient is returned by WithHTTPClient if the client is nil.
+var ErrNilHTTPClient = errors.New("http client must not be nil")
+
 // ErrInsufficientMneeBalance is returned by transfer, partial sign functions when the
 // wallet's UTXOs do not have enough MNEE tokens to cover the transfer amount + fee.
 var ErrInsufficientMneeBalance = errors.New("insufficient mnee balance")
 
+// ErrUnknownSignerAddress is returned by a Signer asked to sign for an address it does not control.
+var ErrUnknownSignerAddress = errors.New("address not controlled by signer")
+
+// ErrNilPrivateKey is returned by NewKeySigner if one of the keys is nil.
+var ErrNilPrivateKey = errors.New("private key must not be nil")
+
+// ErrRemoteSigner is returned by RemoteSigner when the signing service reports an error.
+var ErrRemoteSigner = errors.New("remote signer error")
+
+// ErrNilRemoteSignerAuthorizer is returned by NewRemoteSignerHandler when no
+// authorizer is given: the handler would sign for anyone who can reach it.
+var ErrNilRemoteSignerAuthorizer = errors.New("remote signer handler requires an authorizer")
+
+// ErrEmptyWebhookSecret is returned by NewWebhookHandler for an empty callback
+// secret, which would authenticate callbacks carrying an empty one.
+var ErrEmptyWebhookSecret = errors.New("webhook secret must not be empty")
+
+// ErrUnauthorized is returned by BearerTokenAuthorizer for a request without the expected token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrHDKeyNotPrivate is returned by NewHDWallet if the master key is nil or a public (neutered) key.
+var ErrHDKeyNotPrivate = errors.New("hd wallet requires a private master key")
+
+// ErrInvalidGapLimit is returned by WithGapLimit if the gap limit is 0.
+var ErrInvalidGapLimit = errors.New("gap limit must be greater than 0")
+
+// ErrInvalidHDChain is returned by HDWallet.DeriveAddress for a chain other than receive (0) or change (1).
+var ErrInvalidHDChain = errors.New("invalid hd chain")
+
+// ErrInvalidAmount is returned when a decimal amount string cannot be parsed.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ErrAmountOverflow is returned when an amount does not fit in 64-bit atomic units.
+var ErrAmountOverflow = errors.New("amount overflows atomic units")
+
+// ErrNegativeAmount is returned by Amount.Sub when the result would be negative.
+var ErrNegativeAmount = errors.New("amount would be negative")
+
+// ErrAmountDecimalsMismatch is returned when amounts of different decimals are
+// combined, or an Amount does not match the token's decimals.
+var ErrAmountDecimalsMismatch = errors.New("amount decimals mismatch")
+
+// ErrUTXOsReserved is returned when a transfer keeps losing its selected UTXOs
+// to concurrent transfers of a client using WithUTXOReservation.
+var ErrUTXOsReserved = errors.New("utxos reserved by concurrent transfers")
+
+// ErrNilSigner is returned when an operation requiring a Signer is given none.
+var ErrNilSigner = errors.New("signer must not be nil")
+
+// ErrPayoutManifestMismatch is returned by BatchPayout when the manifest at
+// ManifestPath belongs to a different list of recipients.
+var ErrPayoutManifestMismatch = errors.New("payout manifest does not match the recipients")
+
+// ErrInvalidTxid is matched by the *InvalidIDError returned for a txid that is not 64 hex characters.
+var ErrInvalidTxid = errors.New("invalid txid")
+
+// ErrInvalidOutpoint is matched by the *InvalidIDError returned for an outpoint not of the form "txid_vout".
+var ErrInvalidOutpoint = errors.New("invalid outpoint")
+
+// ErrInvalidTicketID is matched by the *InvalidIDError returned for a malformed ticket ID.
+var ErrInvalidTicketID = errors.New("invalid ticket ID")
+
+// ErrNilAuthenticator is returned by WithAuthenticator when given a nil Authenticator.
+var ErrNilAuthenticator = errors.New("authenticator must not be nil")
+
+// ErrNoTransferStore is returned by Resume on a client without WithTransferStore.
+var ErrNoTransferStore = errors.New("client has no transfer store")
+
 // ErrTransferAmountGreaterThan0 is returned by transfer, partial sign functions
 // if any recipient amount is 0.
 var ErrTransferAmountGreaterThan0 = errors.New("transfer amount must be greater than 0")
@@ -29,10 +117,27 @@ var ErrTransferAmountGreaterThan0 = errors.New("transfer amount must be greater
 // if the provided address's public key hash is not 20 bytes.
 var ErrInvalidPublicKeyHash = errors.New("invalid public key hash")
 
+// ErrInvalidAddress is returned by transfer functions if a recipient,
+// fee or change address cannot be decoded.
+var ErrInvalidAddress = errors.New("invalid address")
+
 // ErrReceivedEmptyTicketID is returned by AsynchronousTransfer if the
 // API returns a 200 OK but the response body (ticket ID) is empty.
 var ErrReceivedEmptyTicketID = errors.New("received an empty ticket ID from server")
 
+// ErrTransferAlreadySubmitted is returned by asynchronous submissions when
+// RetryPolicy.SafeSubmit finds, before a retry, that the cosigner already
+// accepted the transaction. The error message includes the cosigned txid.
+var ErrTransferAlreadySubmitted = errors.New("transfer already submitted")
+
+// ErrInputsSpent is returned when an input of a submitted transaction was
+// spent by another transaction, so the submitted one can never land.
+var ErrInputsSpent = errors.New("transaction inputs spent by another transaction")
+
+// ErrTicketFailed is returned by WaitForTicket when the ticket ends with the
+// FAILED status or reports errors. The ticket is returned alongside it.
+var ErrTicketFailed = errors.New("ticket failed")
+
 // TokenOperation defines the type of MNEE-1SAT operation.
 type TokenOperation string
 
@@ -50,10 +155,14 @@ const (
 )
 
 const (
-	// BROADCASTING indicates the transaction is in the mempool.
+	// BROADCASTING indicates the cosigner is broadcasting the transaction.
 	BROADCASTING TicketStatus = "BROADCASTING"
-	// SUCCESS indicates the transaction has been confirmed.
+	// SUCCESS indicates the transaction has been accepted by the network.
 	SUCCESS TicketStatus = "SUCCESS"
+	// MINED indicates the transaction has been included in a block.
+	MINED TicketStatus = "MINED"
+	// FAILED indicates the cosigner rejected or could not broadcast the transaction.
+	FAILED TicketStatus = "FAILED"
 )
 
 const (
@@ -174,10 +283,14 @@ type TransactionHistoryDTO struct {
 }
 
 // BalanceDataDTO represents the MNEE balance for a single address.
+// Amt and Precised are floats for compatibility; use Amount for exact values.
 type BalanceDataDTO struct {
 	Amt      float64 `json:"amt"`
 	Precised float64 `json:"precised"`
 	Address  *string `json:"address"`
+
+	// atomic is Amt decoded without going through float64.
+	atomic *uint64
 }
 
 // BaseTokenInscription defines the common fields for MNEE-1SAT inscriptions.
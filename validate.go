@@ -0,0 +1,228 @@
+package mnee
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+)
+
+// ViolationCode identifies the protocol rule broken by a transaction.
+type ViolationCode string
+
+const (
+	// ViolationInvalidOutput is reported for an output that is not a valid MNEE script.
+	ViolationInvalidOutput ViolationCode = "invalid_output"
+	// ViolationUnknownInput is reported for an input whose UTXO was not provided.
+	ViolationUnknownInput ViolationCode = "unknown_input"
+	// ViolationAmountMismatch is reported when the inputs do not equal the outputs.
+	ViolationAmountMismatch ViolationCode = "amount_mismatch"
+	// ViolationIncorrectFee is reported when the fee differs from the fee tier of the transfer amount.
+	ViolationIncorrectFee ViolationCode = "incorrect_fee"
+	// ViolationMissingFeeOutput is reported when a fee is due but no output pays SystemConfig.FeeAddress.
+	ViolationMissingFeeOutput ViolationCode = "missing_fee_output"
+	// ViolationUnsignedInput is reported for an input without an unlocking script.
+	ViolationUnsignedInput ViolationCode = "unsigned_input"
+	// ViolationSighashFlag is reported for a signature not using ForkID|All|AnyOneCanPay.
+	ViolationSighashFlag ViolationCode = "sighash_flag"
+	// ViolationInvalidSignature is reported for a signature that does not verify
+	// or whose public key does not own the input.
+	ViolationInvalidSignature ViolationCode = "invalid_signature"
+)
+
+// Violation is a single protocol rule broken by a transaction.
+type Violation struct {
+	Code ViolationCode `json:"code"`
+	// Index is the input or output the violation refers to, or -1 for the whole transaction.
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (v Violation) Error() string {
+
+	if v.Index < 0 {
+		return fmt.Sprintf("%s: %s", v.Code, v.Message)
+	}
+
+	return fmt.Sprintf("%s at %d: %s", v.Code, v.Index, v.Message)
+}
+
+// ValidateTransaction checks a partially signed MNEE transaction, such as the
+// output of PartialSign, against the protocol rules before it is submitted.
+// `inputs` are the UTXOs spent by the transaction. It returns every violation
+// found; an empty list means the transaction can be handed to SubmitRawTxSync.
+// The error is only set when the transaction or the system config cannot be loaded.
+func (m *MNEE) ValidateTransaction(ctx context.Context, rawTxHex string, inputs []MneeTxo) (_ []Violation, err error) {
+
+	ctx, span := m.startSpan(ctx, "ValidateTransaction", Attribute{Key: "mnee.input_count", Value: len(inputs)})
+	defer func() { span.End(err) }()
+
+	mneeTransaction, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(Attribute{Key: "mnee.txid", Value: mneeTransaction.TxID().String()})
+
+	config, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.FeeAddress == nil || config.Fees == nil {
+		return nil, ErrInvalidConfig
+	}
+
+	var txosByOutpoint map[string]MneeTxo = make(map[string]MneeTxo, len(inputs))
+	for _, txo := range inputs {
+		if txo.Txid != nil {
+			txosByOutpoint[fmt.Sprintf("%s_%d", *txo.Txid, txo.Vout)] = txo
+		}
+	}
+
+	var violations []Violation = make([]Violation, 0)
+	var inputAddresses []string = make([]string, 0)
+	var totalInput uint64
+
+	for vin, input := range mneeTransaction.Inputs {
+		outpoint := fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)
+		txo, ok := txosByOutpoint[outpoint]
+		if !ok || txo.Script == nil || txo.Data == nil || txo.Data.Bsv21 == nil || len(txo.Owners) == 0 {
+			violations = append(violations, Violation{Code: ViolationUnknownInput, Index: vin, Message: "no MNEE UTXO provided for " + outpoint})
+			continue
+		}
+
+		if !slices.Contains(inputAddresses, txo.Owners[0]) {
+			inputAddresses = append(inputAddresses, txo.Owners[0])
+		}
+		totalInput += txoAmount(txo)
+
+		scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+		if err != nil {
+			violations = append(violations, Violation{Code: ViolationUnknownInput, Index: vin, Message: "undecodable script for " + outpoint})
+			continue
+		}
+
+		input.SetSourceTxOutput(&transaction.TransactionOutput{
+			Satoshis:      uint64(txo.Satoshis),
+			LockingScript: script.NewFromBytes(scriptBytes),
+		})
+
+		violation, ok := validateInputSignature(mneeTransaction, vin, txo.Owners[0])
+		if !ok {
+			violations = append(violations, violation)
+		}
+	}
+
+	var totalOutput uint64
+	var outputs []TransferOutput = make([]TransferOutput, 0, len(mneeTransaction.Outputs))
+	var feePaid uint64
+	var feeOutput bool
+
+	for vout, output := range mneeTransaction.Outputs {
+		decoded, valid := parseMneeScript(strings.Split(output.LockingScript.ToASM(), " "), config)
+		if !valid {
+			violations = append(violations, Violation{Code: ViolationInvalidOutput, Index: vout, Message: "output is not a valid MNEE script"})
+			continue
+		}
+
+		totalOutput += decoded.amount
+		outputs = append(outputs, TransferOutput{Kind: OutputRecipient, Address: decoded.address, Amount: decoded.amount})
+
+		if decoded.address == *config.FeeAddress {
+			feeOutput = true
+			feePaid += decoded.amount
+		}
+	}
+
+	// The fee tier is chosen on the same base as TransferBuilder's.
+	var transferAmount uint64 = feeBase(outputs, inputAddresses, *config.FeeAddress)
+
+	if totalInput != totalOutput {
+		violations = append(violations, Violation{
+			Code:    ViolationAmountMismatch,
+			Index:   -1,
+			Message: fmt.Sprintf("inputs total %d but outputs total %d", totalInput, totalOutput),
+		})
+	}
+
+	fee, ok := TieredFeePolicy(config.Fees, transferAmount)
+	switch {
+	case !ok:
+		violations = append(violations, Violation{
+			Code:    ViolationIncorrectFee,
+			Index:   -1,
+			Message: fmt.Sprintf("no fee tier for transfer amount %d", transferAmount),
+		})
+	case fee.Fee > 0 && !feeOutput:
+		violations = append(violations, Violation{
+			Code:    ViolationMissingFeeOutput,
+			Index:   -1,
+			Message: fmt.Sprintf("fee of %d must be paid to %s", fee.Fee, *config.FeeAddress),
+		})
+	case feePaid != fee.Fee:
+		violations = append(violations, Violation{
+			Code:    ViolationIncorrectFee,
+			Index:   -1,
+			Message: fmt.Sprintf("fee paid is %d but the tier for %d requires %d", feePaid, transferAmount, fee.Fee),
+		})
+	}
+
+	return violations, nil
+}
+
+// validateInputSignature checks that the input is signed by the owner of the
+// spent UTXO with ForkID|All|AnyOneCanPay. The input's source output must be set.
+func validateInputSignature(mneeTransaction *transaction.Transaction, vin int, owner string) (Violation, bool) {
+
+	var expectedFlags sighash.Flag = sighash.ForkID | sighash.All | sighash.AnyOneCanPay
+
+	input := mneeTransaction.Inputs[vin]
+	if input.UnlockingScript == nil || len(*input.UnlockingScript) == 0 {
+		return Violation{Code: ViolationUnsignedInput, Index: vin, Message: "input is not signed"}, false
+	}
+
+	chunks, err := input.UnlockingScript.Chunks()
+	if err != nil || len(chunks) != 2 || len(chunks[0].Data) < 2 {
+		return Violation{Code: ViolationInvalidSignature, Index: vin, Message: "unlocking script is not <signature> <public key>"}, false
+	}
+
+	signatureBytes := chunks[0].Data
+	flags := sighash.Flag(signatureBytes[len(signatureBytes)-1])
+	if flags != expectedFlags {
+		return Violation{
+			Code:    ViolationSighashFlag,
+			Index:   vin,
+			Message: fmt.Sprintf("sighash flag is 0x%02x, expected 0x%02x", uint8(flags), uint8(expectedFlags)),
+		}, false
+	}
+
+	signature, err := primitives.ParseDERSignature(signatureBytes[:len(signatureBytes)-1])
+	if err != nil {
+		return Violation{Code: ViolationInvalidSignature, Index: vin, Message: "signature is not DER encoded"}, false
+	}
+
+	publicKey, err := primitives.PublicKeyFromBytes(chunks[1].Data)
+	if err != nil {
+		return Violation{Code: ViolationInvalidSignature, Index: vin, Message: "invalid public key"}, false
+	}
+
+	address, err := script.NewAddressFromPublicKey(publicKey, true)
+	if err != nil || address.AddressString != owner {
+		return Violation{Code: ViolationInvalidSignature, Index: vin, Message: "public key does not own " + owner}, false
+	}
+
+	signatureHash, err := mneeTransaction.CalcInputSignatureHash(uint32(vin), flags)
+	if err != nil || !signature.Verify(signatureHash, publicKey) {
+		return Violation{Code: ViolationInvalidSignature, Index: vin, Message: "signature does not verify"}, false
+	}
+
+	return Violation{}, true
+}
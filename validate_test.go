@@ -0,0 +1,184 @@
+package mnee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	"github.com/stretchr/testify/assert"
+)
+
+func violationCodes(violations []Violation) []ViolationCode {
+	codes := make([]ViolationCode, 0, len(violations))
+	for _, violation := range violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestValidateTransaction(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	cosender := newTestKey(t)
+	recipient := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000, 1),
+		newTestTxo(t, cosender.address, 5000, 2),
+	})
+
+	validate := func(mneeTransaction *transaction.Transaction, inputs []MneeTxo) []ViolationCode {
+		violations, err := fixture.mnee.ValidateTransaction(context.Background(), mneeTransaction.Hex(), inputs)
+		assertions.NoError(err)
+		return violationCodes(violations)
+	}
+
+	t.Log("Test Case 1: A fully signed transfer has no violations...")
+	mneeTransaction, summary, err := fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif, cosender.wif).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	assertions.Empty(validate(mneeTransaction, summary.Inputs))
+
+	t.Log("Test Case 2: Missing signatures and UTXOs are reported...")
+	mneeTransaction, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif).
+		WithAddresses(cosender.address).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	violations, err := fixture.mnee.ValidateTransaction(context.Background(), mneeTransaction.Hex(), summary.Inputs)
+	assertions.NoError(err)
+	assertions.Equal([]Violation{{Code: ViolationUnsignedInput, Index: 1, Message: "input is not signed"}}, violations)
+	assertions.Equal([]ViolationCode{ViolationUnknownInput, ViolationAmountMismatch}, validate(mneeTransaction, summary.Inputs[:1]))
+
+	t.Log("Test Case 3: Wrong fees are reported...")
+	for _, testCase := range []struct {
+		fee      uint64
+		expected ViolationCode
+	}{
+		{fee: 10, expected: ViolationIncorrectFee},
+		{fee: 0, expected: ViolationMissingFeeOutput},
+	} {
+		mneeTransaction, summary, err = fixture.mnee.NewTransferBuilder().
+			WithWIFs(sender.wif, cosender.wif).
+			AddRecipient(recipient.address, 4000).
+			WithFeePolicy(func(fees []Fee, amount uint64) (Fee, bool) {
+				return Fee{Fee: testCase.fee}, true
+			}).
+			Sign(context.Background())
+		if !assertions.NoError(err) {
+			return
+		}
+		assertions.Equal([]ViolationCode{testCase.expected}, validate(mneeTransaction, summary.Inputs))
+	}
+
+	t.Log("Test Case 4: Signatures must use ForkID|All|AnyOneCanPay...")
+	mneeTransaction, summary, err = fixture.mnee.NewTransferBuilder().
+		AddRecipient(recipient.address, 4000).
+		WithAddresses(sender.address, cosender.address).
+		Build(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	signer, err := NewWIFSigner(sender.wif, cosender.wif)
+	if !assertions.NoError(err) {
+		return
+	}
+	for i, txo := range summary.Inputs {
+		mneeTransaction.Inputs[i].UnlockingScriptTemplate = &signerUnlocker{
+			ctx:          context.Background(),
+			signer:       signer,
+			address:      txo.Owners[0],
+			sighashFlags: sighash.ForkID | sighash.All,
+		}
+	}
+	if !assertions.NoError(mneeTransaction.Sign()) {
+		return
+	}
+	assertions.Equal([]ViolationCode{ViolationSighashFlag, ViolationSighashFlag}, validate(mneeTransaction, summary.Inputs))
+
+	t.Log("Test Case 5: Non-MNEE outputs are rejected...")
+	mneeTransaction, summary, err = fixture.mnee.NewTransferBuilder().
+		WithWIFs(sender.wif, cosender.wif).
+		AddRecipient(recipient.address, 4000).
+		Sign(context.Background())
+	if !assertions.NoError(err) {
+		return
+	}
+	recipientAddress, err := script.NewAddressFromString(recipient.address)
+	if !assertions.NoError(err) {
+		return
+	}
+	p2pkhScript, err := p2pkh.Lock(recipientAddress)
+	if !assertions.NoError(err) {
+		return
+	}
+	mneeTransaction.AddOutput(&transaction.TransactionOutput{Satoshis: 1, LockingScript: p2pkhScript})
+	assertions.Equal([]ViolationCode{ViolationInvalidSignature, ViolationInvalidSignature, ViolationInvalidOutput},
+		validate(mneeTransaction, summary.Inputs), "The extra output also invalidates the SIGHASH_ALL signatures")
+}
+
+func TestFeeBase_BuilderMatchesValidator(t *testing.T) {
+	assertions := assert.New(t)
+
+	sender := newTestKey(t)
+	cosender := newTestKey(t)
+	recipient := newTestKey(t)
+	external := newTestKey(t)
+	fixture := newTestFixture(t, []MneeTxo{
+		newTestTxo(t, sender.address, 3000000, 1),
+		newTestTxo(t, cosender.address, 2000000, 2),
+	})
+
+	cases := []struct {
+		name       string
+		recipients []TransferMneeDTO
+		change     string
+		outputs    int
+	}{
+		{name: "recipient with change to an input", recipients: []TransferMneeDTO{{Address: recipient.address, Amount: 4000}}},
+		{name: "self-transfer", recipients: []TransferMneeDTO{{Address: sender.address, Amount: 4000}}},
+		{name: "recipient above the first tier", recipients: []TransferMneeDTO{{Address: recipient.address, Amount: 2500000}}},
+		{name: "external change", recipients: []TransferMneeDTO{{Address: recipient.address, Amount: 4000}}, change: external.address},
+		{name: "self-transfer with external change", recipients: []TransferMneeDTO{{Address: cosender.address, Amount: 4000}}, change: external.address},
+		{name: "split external change", recipients: []TransferMneeDTO{{Address: recipient.address, Amount: 3500000}}, change: external.address, outputs: 3},
+	}
+
+	t.Log("Test Case 1: Every fee charged by the builder is the fee the validator requires...")
+	for _, testCase := range cases {
+		builder := fixture.mnee.NewTransferBuilder().
+			WithWIFs(sender.wif, cosender.wif).
+			AddRecipients(testCase.recipients...).
+			WithChangeAddress(testCase.change)
+		if testCase.outputs > 0 {
+			builder.WithChangeOutputs(testCase.outputs)
+		}
+
+		mneeTransaction, summary, err := builder.Sign(context.Background())
+		if !assertions.NoError(err, testCase.name) {
+			continue
+		}
+
+		violations, err := fixture.mnee.ValidateTransaction(context.Background(), mneeTransaction.Hex(), summary.Inputs)
+		assertions.NoError(err, testCase.name)
+		assertions.Empty(violations, testCase.name)
+
+		inputAddresses := make([]string, 0, len(summary.Inputs))
+		for _, txo := range summary.Inputs {
+			inputAddresses = append(inputAddresses, txo.Owners[0])
+		}
+		fee, ok := TieredFeePolicy(fixture.config.Fees, feeBase(summary.Outputs, inputAddresses, *fixture.config.FeeAddress))
+		if assertions.True(ok, testCase.name) {
+			assertions.Equal(fee.Fee, summary.Fee, testCase.name)
+		}
+	}
+}
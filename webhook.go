@@ -0,0 +1,212 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultReplayWindow is how long WebhookHandler remembers delivered callbacks.
+const DefaultReplayWindow time.Duration = 24 * time.Hour
+
+// DefaultMaxWebhookBodySize is the largest callback body WebhookHandler reads, 1 MiB.
+const DefaultMaxWebhookBodySize int64 = 1 << 20
+
+// WebhookCallback handles a decoded ticket callback. Returning an error
+// answers the cosigner with 500 so that the callback can be delivered again.
+type WebhookCallback func(ctx context.Context, ticket *Ticket) error
+
+// WebhookHandler is an http.Handler receiving the callbacks of AsynchronousTransfer
+// and SubmitRawTxAsync. It authenticates every callback with the callbackSecret
+// given at submission, drops replays of the same ticket ID and status, and
+// dispatches the ticket to the callback matching its status. A ticket ID and
+// status count as delivered once their callback succeeds; a redelivery that
+// arrives while the callback still runs is answered with 409 so that it is
+// delivered again later.
+//
+// Set the callbacks before serving requests.
+type WebhookHandler struct {
+	// OnBroadcasting is called for BROADCASTING tickets.
+	OnBroadcasting WebhookCallback
+	// OnSuccess is called for SUCCESS and MINED tickets.
+	OnSuccess WebhookCallback
+	// OnError is called for FAILED tickets and tickets reporting errors.
+	OnError WebhookCallback
+	// ReplayWindow is how long a delivered ticket ID and status are remembered.
+	ReplayWindow time.Duration
+	// MaxBodySize caps the callback body; larger bodies are answered with 413.
+	// Zero means DefaultMaxWebhookBodySize.
+	MaxBodySize int64
+
+	secret   string
+	mutex    sync.Mutex
+	inFlight map[string]bool
+	seen     map[string]time.Time
+	// delivered lists the keys of seen in delivery order, for eviction.
+	delivered []string
+}
+
+// webhookDelivery is what WebhookHandler does with an authenticated callback.
+type webhookDelivery int
+
+const (
+	// webhookDispatch means the callback is new and is dispatched.
+	webhookDispatch webhookDelivery = iota
+	// webhookReplay means the callback was already delivered.
+	webhookReplay
+	// webhookInFlight means the same callback is being dispatched right now.
+	webhookInFlight
+)
+
+// NewWebhookHandler creates a WebhookHandler accepting callbacks carrying
+// `secret`. It returns ErrEmptyWebhookSecret for an empty secret, which would
+// authenticate any callback carrying an empty one.
+func NewWebhookHandler(secret string) (*WebhookHandler, error) {
+
+	if secret == "" {
+		return nil, ErrEmptyWebhookSecret
+	}
+
+	return &WebhookHandler{
+		ReplayWindow: DefaultReplayWindow,
+		MaxBodySize:  DefaultMaxWebhookBodySize,
+		secret:       secret,
+		inFlight:     make(map[string]bool),
+		seen:         make(map[string]time.Time),
+	}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var maxBodySize int64 = h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxWebhookBodySize
+	}
+
+	var ticket Ticket
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&ticket)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err != nil || ticket.ID == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if ticket.CallbackSecret == nil || subtle.ConstantTimeCompare([]byte(*ticket.CallbackSecret), []byte(h.secret)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var replayKey string = *ticket.ID + "|" + string(ticket.Status)
+	switch h.claim(replayKey) {
+	case webhookReplay:
+		w.WriteHeader(http.StatusOK)
+		return
+	case webhookInFlight:
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	var callback WebhookCallback
+	switch {
+	case len(ticket.Errors) > 0 || ticket.Status == FAILED:
+		callback = h.OnError
+	case ticket.Status == BROADCASTING:
+		callback = h.OnBroadcasting
+	case ticket.Status == SUCCESS || ticket.Status == MINED:
+		callback = h.OnSuccess
+	}
+
+	if callback != nil {
+		err = callback(r.Context(), &ticket)
+	}
+
+	h.release(replayKey, err == nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// claim marks a callback as being dispatched, unless it was already delivered
+// within the replay window or is being dispatched by another request. Expired
+// deliveries are evicted from the front of the delivery order on the way.
+func (h *WebhookHandler) claim(replayKey string) webhookDelivery {
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var now time.Time = time.Now()
+	for len(h.delivered) > 0 && now.Sub(h.seen[h.delivered[0]]) > h.ReplayWindow {
+		delete(h.seen, h.delivered[0])
+		h.delivered = h.delivered[1:]
+	}
+
+	if _, ok := h.seen[replayKey]; ok {
+		return webhookReplay
+	}
+
+	if h.inFlight[replayKey] {
+		return webhookInFlight
+	}
+
+	h.inFlight[replayKey] = true
+	return webhookDispatch
+}
+
+// release ends the dispatch of a callback. A delivered callback is remembered
+// for the replay window; a failed one can be delivered again.
+func (h *WebhookHandler) release(replayKey string, delivered bool) {
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.inFlight, replayKey)
+	if delivered {
+		h.seen[replayKey] = time.Now()
+		h.delivered = append(h.delivered, replayKey)
+	}
+}
+
+// NewWebhookPayload encodes a ticket as the cosigner delivers it to the
+// callback URL, carrying `secret`. Use it to test WebhookHandler integrations.
+func NewWebhookPayload(ticket Ticket, secret string) ([]byte, error) {
+
+	ticket.CallbackSecret = &secret
+	return json.Marshal(&ticket)
+}
+
+// NewWebhookRequest builds a callback request for `ticket` to `callbackURL`,
+// authenticated with `secret`, ready for WebhookHandler.ServeHTTP.
+func NewWebhookRequest(callbackURL string, ticket Ticket, secret string) (*http.Request, error) {
+
+	payload, err := NewWebhookPayload(ticket, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	return request, nil
+}
@@ -0,0 +1,120 @@
+package mnee
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	assertions := assert.New(t)
+
+	var delivered []string
+	var failNext bool
+	record := func(kind string) WebhookCallback {
+		return func(ctx context.Context, ticket *Ticket) error {
+			if failNext {
+				failNext = false
+				return errors.New("database unavailable")
+			}
+			delivered = append(delivered, kind+":"+*ticket.ID)
+			return nil
+		}
+	}
+
+	handler, err := NewWebhookHandler("s3cret")
+	require.NoError(t, err)
+	handler.OnBroadcasting = record("broadcasting")
+	handler.OnSuccess = record("success")
+	handler.OnError = record("error")
+
+	deliver := func(ticket Ticket, secret string) int {
+		request, err := NewWebhookRequest("http://localhost/webhook", ticket, secret)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	id := "ticket-1"
+
+	t.Log("Test Case 1: Authenticated callbacks are dispatched by status...")
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &id, Status: BROADCASTING}, "s3cret"))
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &id, Status: SUCCESS}, "s3cret"))
+	assertions.Equal([]string{"broadcasting:ticket-1", "success:ticket-1"}, delivered)
+
+	t.Log("Test Case 2: Replays are acknowledged but not dispatched...")
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &id, Status: SUCCESS}, "s3cret"))
+	assertions.Len(delivered, 2)
+
+	t.Log("Test Case 3: Wrong secrets and malformed bodies are rejected...")
+	other := "ticket-2"
+	assertions.Equal(http.StatusUnauthorized, deliver(Ticket{ID: &other, Status: FAILED}, "guess"))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte("{"))))
+	assertions.Equal(http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	assertions.Equal(http.StatusMethodNotAllowed, recorder.Code)
+
+	t.Log("Test Case 4: A failing callback returns 500 and the redelivery is dispatched...")
+	failNext = true
+	assertions.Equal(http.StatusInternalServerError, deliver(Ticket{ID: &other, Status: FAILED}, "s3cret"))
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &other, Status: FAILED}, "s3cret"))
+	assertions.Equal([]string{"broadcasting:ticket-1", "success:ticket-1", "error:ticket-2"}, delivered)
+
+	t.Log("Test Case 5: Oversized bodies are rejected with 413 before dispatch...")
+	large := "ticket-3"
+	handler.MaxBodySize = 64
+	oversized := Ticket{ID: &large, Status: SUCCESS, Errors: []string{strings.Repeat("x", 128)}}
+	assertions.Equal(http.StatusRequestEntityTooLarge, deliver(oversized, "s3cret"))
+	assertions.Len(delivered, 3)
+
+	handler.MaxBodySize = 0
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &large, Status: SUCCESS}, "s3cret"), "zero uses the default limit")
+	assertions.Len(delivered, 4)
+
+	t.Log("Test Case 6: A redelivery during a failing dispatch is not acknowledged...")
+	slow := "ticket-4"
+	started, release := make(chan struct{}), make(chan struct{})
+	handler.OnSuccess = func(ctx context.Context, ticket *Ticket) error {
+		close(started)
+		<-release
+		return errors.New("database unavailable")
+	}
+	first := make(chan int)
+	go func() {
+		first <- deliver(Ticket{ID: &slow, Status: SUCCESS}, "s3cret")
+	}()
+	<-started
+	assertions.Equal(http.StatusConflict, deliver(Ticket{ID: &slow, Status: SUCCESS}, "s3cret"))
+	close(release)
+	assertions.Equal(http.StatusInternalServerError, <-first)
+
+	handler.OnSuccess = record("success")
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &slow, Status: SUCCESS}, "s3cret"))
+	assertions.Equal("success:ticket-4", delivered[len(delivered)-1], "The failed event is dispatched again")
+
+	t.Log("Test Case 7: Deliveries are evicted after the replay window...")
+	handler.ReplayWindow = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	assertions.Equal(http.StatusOK, deliver(Ticket{ID: &id, Status: SUCCESS}, "s3cret"))
+	assertions.Equal("success:ticket-1", delivered[len(delivered)-1])
+	assertions.Len(handler.seen, 1, "Only the last delivery is still remembered")
+	assertions.Len(handler.delivered, 1)
+
+	t.Log("Test Case 8: An empty secret is rejected...")
+	_, err = NewWebhookHandler("")
+	assertions.ErrorIs(err, ErrEmptyWebhookSecret)
+}